@@ -0,0 +1,217 @@
+package schedulesdirect
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type artworkImage struct {
+	Width    string `json:"width"`
+	Height   string `json:"height"`
+	Uri      string `json:"uri"`
+	Size     string `json:"size"`
+	Aspect   string `json:"aspect"`   // 16x9, 4x3, 2x3...
+	Category string `json:"category"` // Banner, Iconic, Poster Art...
+	Text     string `json:"text"`
+	Primary  string `json:"primary"`
+	Tier     string `json:"tier"` // Series, Season, Episode
+}
+
+func (i artworkImage) width() int {
+	w, _ := strconv.Atoi(i.Width)
+	return w
+}
+
+// ratio returns the aspect as width/height, 0 when unknown.
+func (i artworkImage) ratio() float64 {
+	parts := strings.Split(i.Aspect, "x")
+	if len(parts) == 2 {
+		w, errW := strconv.ParseFloat(parts[0], 64)
+		h, errH := strconv.ParseFloat(parts[1], 64)
+		if errW == nil && errH == nil && h > 0 {
+			return w / h
+		}
+	}
+
+	w, errW := strconv.ParseFloat(i.Width, 64)
+	h, errH := strconv.ParseFloat(i.Height, 64)
+	if errW == nil && errH == nil && h > 0 {
+		return w / h
+	}
+
+	return 0
+}
+
+type programArtwork struct {
+	ProgramID string          `json:"programID"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// GetProgramArtwork returns the images of programIDs, full programIDs or
+// series roots (SH01234567). The programs without artwork are omitted.
+func (c sdclient) GetProgramArtwork(token string, programIDs []string) (map[string][]artworkImage, error) {
+	if len(programIDs) == 0 {
+		return map[string][]artworkImage{}, errors.New("programIDs slice is empty")
+	}
+
+	var buf bytes.Buffer
+
+	errEncode := json.NewEncoder(&buf).Encode(request{programIDs})
+	if errEncode != nil {
+		return map[string][]artworkImage{}, errEncode
+	}
+
+	var clientHttp http.Client
+
+	req, errNewRequest := http.NewRequest("POST", c.baseURL+apiVersion+"/metadata/programs", &buf)
+	if errNewRequest != nil {
+		return map[string][]artworkImage{}, errNewRequest
+	}
+
+	req.Header.Add("User-Agent", "go-schedulesdirect")
+	req.Header.Add("token", token)
+	req.Header.Add("Accept-Encoding", "deflate")
+
+	resp, errDo := clientHttp.Do(req)
+	if errDo != nil {
+		return map[string][]artworkImage{}, errDo
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 && resp.StatusCode != 400 {
+		return map[string][]artworkImage{}, fmt.Errorf("resp.StatusCode != 200: %d", resp.StatusCode)
+	}
+
+	body, errBody := decodedBody(resp)
+	if errBody != nil {
+		return map[string][]artworkImage{}, errBody
+	}
+	defer body.Close()
+
+	var raw json.RawMessage
+
+	errDecode := json.NewDecoder(body).Decode(&raw)
+	if errDecode != nil {
+		return map[string][]artworkImage{}, errDecode
+	}
+
+	var artwork []programArtwork
+
+	errUnmarshal := json.Unmarshal(raw, &artwork)
+	if errUnmarshal != nil {
+		// when there's an error, the service use another JSON format
+		var cm codeMessage
+
+		errUnmarshal2 := json.Unmarshal(raw, &cm)
+		if errUnmarshal2 != nil || cm.Message == "" {
+			return map[string][]artworkImage{}, errUnmarshal
+		}
+		return map[string][]artworkImage{}, errors.New(cm.Message)
+	}
+
+	result := make(map[string][]artworkImage, len(artwork))
+
+	for _, a := range artwork {
+		var images []artworkImage
+
+		// data is an error object when there's no image
+		if json.Unmarshal(a.Data, &images) != nil || len(images) == 0 {
+			continue
+		}
+
+		result[a.ProgramID] = images
+	}
+
+	return result, nil
+}
+
+// ImageURL returns the absolute URL of an image URI, the URIs of the service
+// are relative to the image endpoint.
+func (c sdclient) ImageURL(uri string) string {
+	if strings.HasPrefix(uri, "http://") || strings.HasPrefix(uri, "https://") {
+		return uri
+	}
+	return c.baseURL + apiVersion + "/image/" + strings.TrimPrefix(uri, "/")
+}
+
+// ImageRequest describes the wanted image: Aspect like "16x9", the minimum
+// Width and the Categories in order of preference. All are optional.
+type ImageRequest struct {
+	Aspect     string
+	Width      int
+	Categories []string
+}
+
+func (r ImageRequest) categoryRank(category string) int {
+	for i, c := range r.Categories {
+		if strings.EqualFold(c, category) || strings.HasPrefix(strings.ToLower(category), strings.ToLower(c)+"-") {
+			return i
+		}
+	}
+	return len(r.Categories)
+}
+
+// better tells if a is a better match than b: closest aspect, preferred
+// category, smallest width at least r.Width (else the widest), primary.
+func (r ImageRequest) better(a, b artworkImage) bool {
+	if r.Aspect != "" {
+		want := artworkImage{Aspect: r.Aspect}.ratio()
+		da, db := math.Abs(a.ratio()-want), math.Abs(b.ratio()-want)
+		if da != db {
+			return da < db
+		}
+	}
+
+	if ra, rb := r.categoryRank(a.Category), r.categoryRank(b.Category); ra != rb {
+		return ra < rb
+	}
+
+	wa, wb := a.width(), b.width()
+	if wa != wb {
+		switch {
+		case wa >= r.Width && wb >= r.Width:
+			return wa < wb
+		case wa >= r.Width || wb >= r.Width:
+			return wa >= r.Width
+		default:
+			return wa > wb
+		}
+	}
+
+	return a.Primary == "true" && b.Primary != "true"
+}
+
+// SelectImage picks the best image of programID, falling back to the series
+// artwork (SH programID and series root) for episodes.
+func SelectImage(artwork map[string][]artworkImage, programID string, r ImageRequest) (artworkImage, bool) {
+	keys := []string{programID}
+	if id, err := ParseProgramID(programID); err == nil {
+		if seriesID, ok := id.SeriesID(); ok {
+			keys = append(keys, seriesID, seriesID[:10])
+		}
+		keys = append(keys, programID[:10])
+	}
+
+	for _, key := range keys {
+		images := artwork[key]
+		if len(images) == 0 {
+			continue
+		}
+
+		best := images[0]
+		for _, i := range images[1:] {
+			if r.better(i, best) {
+				best = i
+			}
+		}
+		return best, true
+	}
+
+	return artworkImage{}, false
+}
@@ -0,0 +1,96 @@
+package schedulesdirect
+
+import (
+	"compress/zlib"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestGetProgramArtworkOK(t *testing.T) {
+	setup()
+
+	mux.HandleFunc(apiVersion+"/metadata/programs",
+		func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "POST")
+			testHeader(t, r, "token", "token1")
+			testPayload(t, r, []byte(`{"request":["EP012345670001","SH01234567"]}`+"\n"))
+
+			fmt.Fprint(w, `[{"programID":"EP012345670001","data":{"errorCode":5102,"errorMessage":"No data for programID."}},{"programID":"SH01234567","data":[{"width":"135","height":"180","uri":"assets/p1_v_aa.jpg","size":"Sm","aspect":"3x4","category":"Poster Art","tier":"Series"},{"width":"1280","height":"720","uri":"assets/p1_h_aa.jpg","size":"Lg","aspect":"16x9","category":"Iconic","tier":"Series"},{"width":"240","height":"135","uri":"assets/p1_b_aa.jpg","size":"Sm","aspect":"16x9","category":"Banner-L1","primary":"true","tier":"Series"},{"width":"480","height":"270","uri":"assets/p1_i_aa.jpg","size":"Md","aspect":"16x9","category":"Iconic","tier":"Series"}]}]`)
+		},
+	)
+
+	artwork, err := client.GetProgramArtwork("token1", []string{"EP012345670001", "SH01234567"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(artwork) != 1 || len(artwork["SH01234567"]) != 4 {
+		t.Fatalf("artwork doesn't match: %v", artwork)
+	}
+
+	i, ok := SelectImage(artwork, "EP012345670001", ImageRequest{Aspect: "16x9", Width: 400, Categories: []string{"Iconic", "Banner"}})
+	if !ok || i.Uri != "assets/p1_i_aa.jpg" {
+		t.Fatalf("i doesn't match: %v", i)
+	}
+
+	i, _ = SelectImage(artwork, "EP012345670001", ImageRequest{Aspect: "16x9", Categories: []string{"Banner"}})
+	if i.Uri != "assets/p1_b_aa.jpg" {
+		t.Fatalf("i doesn't match: %v", i)
+	}
+
+	i, _ = SelectImage(artwork, "EP012345670001", ImageRequest{Aspect: "2x3", Width: 2000})
+	if i.Uri != "assets/p1_v_aa.jpg" {
+		t.Fatalf("i doesn't match: %v", i)
+	}
+
+	if _, ok := SelectImage(artwork, "MV000123450000", ImageRequest{}); ok {
+		t.Fatal("MV000123450000 shouldn't have an image")
+	}
+
+	if u := client.ImageURL(i.Uri); u != server.URL+apiVersion+"/image/assets/p1_v_aa.jpg" {
+		t.Fatalf("u doesn't match: %s", u)
+	}
+}
+
+func TestGetProgramArtworkFailsWithMessage(t *testing.T) {
+	setup()
+
+	mux.HandleFunc(apiVersion+"/metadata/programs",
+		func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "", http.StatusBadRequest)
+			fmt.Fprint(w, `{"response":"INVALID_USER","code":4003,"serverID":"serverID1","message":"Invalid user.","datetime":"2014-07-29T01:00:28Z"}`)
+		},
+	)
+
+	_, err := client.GetProgramArtwork("token1", []string{"SH01234567"})
+	if err == nil {
+		t.Fatal("err == nil")
+	} else if err.Error() != "Invalid user." {
+		t.Fatal(err)
+	}
+}
+
+func TestGetProgramArtworkDeflate(t *testing.T) {
+	setup()
+
+	mux.HandleFunc(apiVersion+"/metadata/programs",
+		func(w http.ResponseWriter, r *http.Request) {
+			testHeader(t, r, "Accept-Encoding", "deflate")
+
+			w.Header().Set("Content-Encoding", "deflate")
+			zw := zlib.NewWriter(w)
+			fmt.Fprint(zw, `[{"programID":"SH01234567","data":[{"width":"135","height":"180","uri":"assets/p1_v_aa.jpg","aspect":"3x4","category":"Poster Art","tier":"Series"}]}]`)
+			zw.Close()
+		},
+	)
+
+	artwork, err := client.GetProgramArtwork("token1", []string{"SH01234567"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(artwork["SH01234567"]) != 1 {
+		t.Fatalf("artwork doesn't match: %v", artwork)
+	}
+}
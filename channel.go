@@ -0,0 +1,133 @@
+package schedulesdirect
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ChannelNumber is a parsed channel like "2", "007" or the "7.1" (or "7-1")
+// virtual channels used by antenna lineups. Minor is -1 when there's none.
+type ChannelNumber struct {
+	Major int
+	Minor int
+}
+
+func ParseChannelNumber(channel string) (ChannelNumber, error) {
+	s := strings.TrimSpace(channel)
+
+	major, minor := s, ""
+	if i := strings.IndexAny(s, ".-"); i != -1 {
+		major, minor = s[:i], s[i+1:]
+	}
+
+	n, errMajor := strconv.Atoi(major)
+	if errMajor != nil || n < 0 {
+		return ChannelNumber{}, fmt.Errorf("invalid channel: %q", channel)
+	}
+
+	cn := ChannelNumber{Major: n, Minor: -1}
+
+	if minor != "" || len(major) != len(s) {
+		m, errMinor := strconv.Atoi(minor)
+		if errMinor != nil || m < 0 {
+			return ChannelNumber{}, fmt.Errorf("invalid channel: %q", channel)
+		}
+		cn.Minor = m
+	}
+
+	return cn, nil
+}
+
+func (cn ChannelNumber) String() string {
+	if cn.Minor < 0 {
+		return strconv.Itoa(cn.Major)
+	}
+	return fmt.Sprintf("%d.%d", cn.Major, cn.Minor)
+}
+
+// Less orders by major then minor, a channel without minor comes first.
+func (cn ChannelNumber) Less(other ChannelNumber) bool {
+	if cn.Major != other.Major {
+		return cn.Major < other.Major
+	}
+	return cn.Minor < other.Minor
+}
+
+// lessChannel compares two raw channels, the ones that can't be parsed are
+// sorted as strings after the others.
+func lessChannel(a, b string) bool {
+	cnA, errA := ParseChannelNumber(a)
+	cnB, errB := ParseChannelNumber(b)
+
+	switch {
+	case errA == nil && errB == nil:
+		if cnA == cnB {
+			return a < b
+		}
+		return cnA.Less(cnB)
+	case errA == nil:
+		return true
+	case errB == nil:
+		return false
+	default:
+		return a < b
+	}
+}
+
+func sameChannel(a, b string) bool {
+	cnA, errA := ParseChannelNumber(a)
+	cnB, errB := ParseChannelNumber(b)
+	if errA != nil || errB != nil {
+		return strings.TrimSpace(a) == strings.TrimSpace(b)
+	}
+	return cnA == cnB
+}
+
+type channelMapEntries []channelMapEntry
+
+func (m channelMapEntries) Len() int      { return len(m) }
+func (m channelMapEntries) Swap(i, j int) { m[i], m[j] = m[j], m[i] }
+func (m channelMapEntries) Less(i, j int) bool {
+	if m[i].Channel != m[j].Channel {
+		return lessChannel(m[i].Channel, m[j].Channel)
+	}
+	return m[i].StationId < m[j].StationId
+}
+
+// SortMap sorts Map by channel number ("2" before "10", "7.1" before "7.2").
+func (cm *channelMapping) SortMap() {
+	sort.Sort(channelMapEntries(cm.Map))
+}
+
+// StationByChannel returns the stationID on a channel, "7-1" matches "7.1".
+func (cm channelMapping) StationByChannel(channel string) (string, bool) {
+	for _, m := range cm.Map {
+		if sameChannel(m.Channel, channel) {
+			return m.StationId, true
+		}
+	}
+	return "", false
+}
+
+// ChannelsByStation returns the sorted channels of a station, a station can be
+// on several channels.
+func (cm channelMapping) ChannelsByStation(stationID string) []string {
+	channels := []string{}
+	for _, m := range cm.Map {
+		if m.StationId == stationID {
+			channels = append(channels, m.Channel)
+		}
+	}
+
+	sort.Sort(channelStrings(channels))
+
+	return channels
+}
+
+type channelStrings []string
+
+func (c channelStrings) Len() int           { return len(c) }
+func (c channelStrings) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+func (c channelStrings) Less(i, j int) bool { return lessChannel(c[i], c[j]) }
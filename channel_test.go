@@ -0,0 +1,82 @@
+package schedulesdirect
+
+import (
+	"testing"
+)
+
+func TestParseChannelNumber(t *testing.T) {
+	tests := map[string]ChannelNumber{
+		"2":    {2, -1},
+		"007":  {7, -1},
+		"7.1":  {7, 1},
+		"7-1":  {7, 1},
+		"1933": {1933, -1},
+	}
+
+	for in, expect := range tests {
+		cn, err := ParseChannelNumber(in)
+		if err != nil {
+			t.Fatal(err)
+		} else if cn != expect {
+			t.Fatalf("ParseChannelNumber(%q) (%v) != %v", in, cn, expect)
+		}
+	}
+
+	for _, in := range []string{"", "A12", "7.", ".1", "7.1.2"} {
+		if _, err := ParseChannelNumber(in); err == nil {
+			t.Fatalf("ParseChannelNumber(%q) should fail", in)
+		}
+	}
+}
+
+func TestChannelNumberString(t *testing.T) {
+	if s := (ChannelNumber{7, 1}).String(); s != "7.1" {
+		t.Fatalf(`s != "7.1": %s`, s)
+	}
+	if s := (ChannelNumber{12, -1}).String(); s != "12" {
+		t.Fatalf(`s != "12": %s`, s)
+	}
+}
+
+func testChannelMapping() channelMapping {
+	return channelMapping{
+		Map: []channelMapEntry{
+			{"10", "10003"},
+			{"7.2", "10002"},
+			{"2", "10001"},
+			{"7.1", "10002"},
+			{"A1", "10004"},
+			{"7", "10005"},
+		},
+	}
+}
+
+func TestChannelMappingSortMap(t *testing.T) {
+	cm := testChannelMapping()
+	cm.SortMap()
+
+	expect := []string{"2", "7", "7.1", "7.2", "10", "A1"}
+	for i, m := range cm.Map {
+		if m.Channel != expect[i] {
+			t.Fatalf("cm.Map[%d].Channel (%s) != %s", i, m.Channel, expect[i])
+		}
+	}
+}
+
+func TestChannelMappingLookups(t *testing.T) {
+	cm := testChannelMapping()
+
+	stationID, ok := cm.StationByChannel("7-1")
+	if !ok || stationID != "10002" {
+		t.Fatalf(`stationID != "10002": %s`, stationID)
+	}
+
+	if _, ok := cm.StationByChannel("99"); ok {
+		t.Fatal("channel 99 shouldn't be found")
+	}
+
+	channels := cm.ChannelsByStation("10002")
+	if len(channels) != 2 || channels[0] != "7.1" || channels[1] != "7.2" {
+		t.Fatalf("channels don't match: %v", channels)
+	}
+}
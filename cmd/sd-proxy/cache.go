@@ -0,0 +1,137 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// entry is a cached response. Version is what tells if it's still current:
+// the md5 of a program, the modified date of a lineup.
+type entry struct {
+	Key     string    `json:"key"`
+	Fetched time.Time `json:"fetched"`
+	Version string    `json:"version"`
+	Body    []byte    `json:"body"`
+}
+
+// cache keeps the entries in memory and, when dir isn't empty, on disk so they
+// survive a restart.
+type cache struct {
+	dir string
+
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+func newCache(dir string) (*cache, error) {
+	c := &cache{dir: dir, entries: make(map[string]entry)}
+
+	if dir == "" {
+		return c, nil
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	names, errGlob := filepath.Glob(filepath.Join(dir, "*.json"))
+	if errGlob != nil {
+		return nil, errGlob
+	}
+
+	for _, name := range names {
+		data, errRead := ioutil.ReadFile(name)
+		if errRead != nil {
+			return nil, errRead
+		}
+
+		var e entry
+		if err := json.Unmarshal(data, &e); err != nil {
+			// a corrupted entry is only a miss
+			os.Remove(name)
+			continue
+		}
+
+		c.entries[e.Key] = e
+	}
+
+	return c, nil
+}
+
+func (c *cache) fileName(key string) string {
+	h := sha1.Sum([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(h[:])+".json")
+}
+
+func (c *cache) get(key string) (entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	return e, ok
+}
+
+func (c *cache) put(e entry) error {
+	c.mu.Lock()
+	c.entries[e.Key] = e
+	c.mu.Unlock()
+
+	if c.dir == "" {
+		return nil
+	}
+
+	data, errMarshal := json.Marshal(e)
+	if errMarshal != nil {
+		return errMarshal
+	}
+
+	tmp, errTemp := ioutil.TempFile(c.dir, ".entry-")
+	if errTemp != nil {
+		return errTemp
+	}
+	defer os.Remove(tmp.Name())
+
+	_, errWrite := tmp.Write(data)
+	errClose := tmp.Close()
+	if errWrite != nil {
+		return errWrite
+	} else if errClose != nil {
+		return errClose
+	}
+
+	return os.Rename(tmp.Name(), c.fileName(e.Key))
+}
+
+// del removes the entries with a key starting with prefix.
+func (c *cache) del(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.entries, key)
+			if c.dir != "" {
+				os.Remove(c.fileName(key))
+			}
+		}
+	}
+}
+
+// each calls f with the entries with a key starting with prefix.
+func (c *cache) each(prefix string, f func(entry)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, e := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			f(e)
+		}
+	}
+}
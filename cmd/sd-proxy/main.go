@@ -0,0 +1,46 @@
+// sd-proxy is a caching proxy of the Schedules Direct JSON service so several
+// clients can share an account without using its quota. Point the clients at
+// it with schedulesdirect.NewClientWithURL and log in with the proxy's
+// username and password.
+//
+//	sd-proxy -username me -password secret -cache /var/cache/sd-proxy
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+)
+
+func main() {
+	listen := flag.String("listen", ":8080", "address to listen on")
+	upstream := flag.String("upstream", "https://json.schedulesdirect.org", "Schedules Direct URL")
+	username := flag.String("username", "", "Schedules Direct username")
+	password := flag.String("password", "", "Schedules Direct password")
+	dir := flag.String("cache", "", "cache directory, in memory only when empty")
+	statusTTL := flag.Duration("status-ttl", defaultStatusTTL, "how long the status is cached")
+	lineupsTTL := flag.Duration("lineups-ttl", defaultLineupsTTL, "how long the lineups and channel maps are cached")
+	headendsTTL := flag.Duration("headends-ttl", defaultHeadendsTTL, "how long the headends are cached")
+	schedulesTTL := flag.Duration("schedules-ttl", defaultSchedulesTTL, "how often the md5s of the schedules are checked")
+	programsTTL := flag.Duration("programs-ttl", defaultProgramsTTL, "how long the programs without schedule md5 are cached")
+	flag.Parse()
+
+	if *username == "" || *password == "" {
+		log.Fatal("-username and -password are required")
+	}
+
+	c, err := newCache(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	p := newProxy(*upstream, *username, *password, c)
+	p.statusTTL = *statusTTL
+	p.lineupsTTL = *lineupsTTL
+	p.headendsTTL = *headendsTTL
+	p.schedulesTTL = *schedulesTTL
+	p.programsTTL = *programsTTL
+
+	log.Printf("listening on %s", *listen)
+	log.Fatal(http.ListenAndServe(*listen, p.handler()))
+}
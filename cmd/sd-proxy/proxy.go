@@ -0,0 +1,666 @@
+package main
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	schedulesdirect "github.com/brunoqc/go-schedulesdirect"
+)
+
+const (
+	apiVersion = "/20131021"
+
+	// the tokens of the service are valid 24 hours
+	upstreamTokenTTL = 12 * time.Hour
+
+	defaultStatusTTL    = 5 * time.Minute
+	defaultLineupsTTL   = time.Hour
+	defaultHeadendsTTL  = 24 * time.Hour
+	defaultSchedulesTTL = time.Hour
+	defaultProgramsTTL  = 24 * time.Hour
+)
+
+type codeMessage struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type tokenResponse struct {
+	Code     int    `json:"code"`
+	Message  string `json:"message"`
+	ServerID string `json:"serverID"`
+	Token    string `json:"token"`
+}
+
+type request struct {
+	Request []string `json:"request"`
+}
+
+// scheduleMd5 is the md5 of a day of a station's schedule.
+type scheduleMd5 struct {
+	Code int    `json:"code"`
+	Md5  string `json:"md5"`
+}
+
+// scheduleVersion is the version of a station's schedule, made of the md5s of
+// its days.
+type scheduleVersion struct {
+	version string
+	checked time.Time
+}
+
+// upstreamStatus is the part of the status used to invalidate the cache.
+type upstreamStatus struct {
+	Lineups []struct {
+		Modified time.Time `json:"modified"`
+		Uri      string    `json:"uri"`
+	} `json:"lineups"`
+}
+
+// proxy answers the requests of the clients from its cache and fetches the
+// missing or outdated data with its own account.
+type proxy struct {
+	upstream string
+	username string
+	password string
+
+	// given to the clients
+	token string
+
+	cache *cache
+
+	statusTTL    time.Duration
+	lineupsTTL   time.Duration
+	headendsTTL  time.Duration
+	schedulesTTL time.Duration
+	programsTTL  time.Duration
+
+	clientHttp http.Client
+
+	mu                sync.Mutex
+	upstreamToken     string
+	upstreamTokenTime time.Time
+	// set when the upstream deflate-encodes its responses
+	deflate bool
+	// md5 of the programs by programID, from the cached schedules
+	programMd5s map[string]string
+	// versions of the schedules by stationID, from the md5s of the service
+	scheduleVersions map[string]scheduleVersion
+}
+
+func hashPassword(password string) string {
+	h := sha1.New()
+	io.WriteString(h, password)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func newToken() string {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		log.Fatal(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+func newProxy(upstream, username, password string, c *cache) *proxy {
+	p := &proxy{
+		upstream:     strings.TrimSuffix(upstream, "/"),
+		username:     username,
+		password:     password,
+		token:        newToken(),
+		cache:        c,
+		statusTTL:    defaultStatusTTL,
+		lineupsTTL:   defaultLineupsTTL,
+		headendsTTL:  defaultHeadendsTTL,
+		schedulesTTL: defaultSchedulesTTL,
+		programsTTL:  defaultProgramsTTL,
+		programMd5s:  make(map[string]string),
+
+		scheduleVersions: make(map[string]scheduleVersion),
+	}
+
+	c.each("schedule/", func(e entry) {
+		if s, err := schedulesdirect.JsonToSchedules(e.Body); err == nil {
+			for _, sp := range s.Programs {
+				p.programMd5s[sp.ProgramID] = sp.Md5
+			}
+		}
+	})
+
+	return p
+}
+
+func (p *proxy) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(apiVersion+"/token", p.handleToken)
+	mux.HandleFunc(apiVersion+"/status", p.authorized(p.handleStatus))
+	mux.HandleFunc(apiVersion+"/headends", p.authorized(p.handleHeadends))
+	mux.HandleFunc(apiVersion+"/lineups", p.authorized(p.handleLineups))
+	mux.HandleFunc(apiVersion+"/lineups/", p.authorized(p.handleLineup))
+	mux.HandleFunc(apiVersion+"/programs", p.authorized(p.handlePrograms))
+	mux.HandleFunc(apiVersion+"/schedules", p.authorized(p.handleSchedules))
+	mux.HandleFunc(apiVersion+"/", p.authorized(p.handlePassthrough))
+	return mux
+}
+
+// authorized rejects the requests without the token given by handleToken.
+func (p *proxy) authorized(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("token") != p.token {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		h(w, r)
+	}
+}
+
+// handleToken accepts the credentials of the proxy's account, the password
+// being sha1 hashed like the service expects.
+func (p *proxy) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		User string `json:"username"`
+		Pass string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp := tokenResponse{Code: 0, Message: "OK", ServerID: "sd-proxy", Token: p.token}
+	if req.User != p.username || req.Pass != hashPassword(p.password) {
+		resp = tokenResponse{Code: 4003, Message: "Invalid user", ServerID: "sd-proxy"}
+	}
+
+	body, errMarshal := json.Marshal(resp)
+	if errMarshal != nil {
+		http.Error(w, errMarshal.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	p.write(w, r, http.StatusOK, body)
+}
+
+func (p *proxy) handleStatus(w http.ResponseWriter, r *http.Request) {
+	p.cachedGet(w, r, "status", p.statusTTL, "")
+}
+
+func (p *proxy) handleHeadends(w http.ResponseWriter, r *http.Request) {
+	p.cachedGet(w, r, "headends?"+r.URL.RawQuery, p.headendsTTL, "")
+}
+
+func (p *proxy) handleLineups(w http.ResponseWriter, r *http.Request) {
+	p.cachedGet(w, r, "lineups", p.lineupsTTL, "")
+}
+
+// handleLineup returns a channel mapping, refreshed when the lineup's modified
+// date of the status changes. Adding or deleting a lineup is forwarded.
+func (p *proxy) handleLineup(w http.ResponseWriter, r *http.Request) {
+	if r.Method == "PUT" || r.Method == "DELETE" {
+		status, body, err := p.fetch(r.Method, r.URL.Path, nil)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		p.cache.del("status")
+		p.cache.del("lineups")
+		p.cache.del("map " + r.URL.Path)
+
+		p.write(w, r, status, body)
+		return
+	}
+
+	version := ""
+	if s, err := p.status(); err == nil {
+		for _, l := range s.Lineups {
+			if l.Uri == r.URL.Path {
+				version = l.Modified.Format(time.RFC3339)
+			}
+		}
+	} else {
+		log.Printf("status: %s", err)
+	}
+
+	p.cachedGet(w, r, "map "+r.URL.Path, p.lineupsTTL, version)
+}
+
+func (p *proxy) handlePassthrough(w http.ResponseWriter, r *http.Request) {
+	body, errRead := ioutil.ReadAll(r.Body)
+	if errRead != nil {
+		http.Error(w, errRead.Error(), http.StatusBadRequest)
+		return
+	}
+
+	status, data, err := p.fetch(r.Method, r.URL.RequestURI(), body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	p.write(w, r, status, data)
+}
+
+// get returns a cached GET response fetched less than ttl ago with the same
+// version, or fetches it. Only the 200 responses are cached.
+func (p *proxy) get(key, uri string, ttl time.Duration, version string) (int, []byte, error) {
+	if e, ok := p.cache.get(key); ok && time.Since(e.Fetched) < ttl && e.Version == version {
+		return http.StatusOK, e.Body, nil
+	}
+
+	status, body, err := p.fetch("GET", uri, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if status == http.StatusOK {
+		if err := p.cache.put(entry{key, time.Now(), version, body}); err != nil {
+			log.Printf("cache %s: %s", key, err)
+		}
+	}
+
+	return status, body, nil
+}
+
+func (p *proxy) cachedGet(w http.ResponseWriter, r *http.Request, key string, ttl time.Duration, version string) {
+	if r.Method != "GET" {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status, body, err := p.get(key, r.URL.RequestURI(), ttl, version)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	p.write(w, r, status, body)
+}
+
+func (p *proxy) status() (upstreamStatus, error) {
+	_, body, err := p.get("status", apiVersion+"/status", p.statusTTL, "")
+	if err != nil {
+		return upstreamStatus{}, err
+	}
+
+	var s upstreamStatus
+	errUnmarshal := json.Unmarshal(body, &s)
+	return s, errUnmarshal
+}
+
+// programCurrent compares a cached program with the md5 of the last schedules.
+// When no schedule gives its md5, the program is current for programsTTL.
+func (p *proxy) programCurrent(e entry) bool {
+	p.mu.Lock()
+	current, ok := p.programMd5s[strings.TrimPrefix(e.Key, "program/")]
+	p.mu.Unlock()
+
+	if !ok {
+		return time.Since(e.Fetched) < p.programsTTL
+	}
+	return current == e.Version
+}
+
+// handlePrograms answers from the cached programs, the ones missing, with a
+// md5 different from the one of the last schedules or fetched more than
+// programsTTL ago without a md5 are fetched.
+func (p *proxy) handlePrograms(w http.ResponseWriter, r *http.Request) {
+	p.handleLines(w, r, "/programs", "program/", nil, func(line []byte) (string, string, bool) {
+		prog, err := schedulesdirect.JsonToProgram(line)
+		if err != nil || prog.Code != 0 || prog.ProgramID == "" {
+			return "", "", false
+		}
+		return prog.ProgramID, prog.Md5, true
+	}, p.programCurrent)
+}
+
+// handleSchedules answers from the cached schedules, only the ones missing or
+// whose md5s changed on the service are fetched. The md5s are checked at most
+// every schedulesTTL; when they can't be, a schedule is current for
+// schedulesTTL.
+func (p *proxy) handleSchedules(w http.ResponseWriter, r *http.Request) {
+	var versions map[string]string
+
+	p.handleLines(w, r, "/schedules", "schedule/", func(ids []string) {
+		versions = p.scheduleVersionsOf(ids)
+	}, func(line []byte) (string, string, bool) {
+		// the errors have a different stationID format
+		var cm codeMessage
+		if err := json.Unmarshal(line, &cm); err != nil || cm.Message != "" {
+			return "", "", false
+		}
+
+		s, err := schedulesdirect.JsonToSchedules(line)
+		if err != nil || s.StationID == "" {
+			return "", "", false
+		}
+
+		p.mu.Lock()
+		for _, sp := range s.Programs {
+			p.programMd5s[sp.ProgramID] = sp.Md5
+		}
+		p.mu.Unlock()
+
+		return s.StationID, versions[s.StationID], true
+	}, func(e entry) bool {
+		if version, ok := versions[strings.TrimPrefix(e.Key, "schedule/")]; ok {
+			return e.Version == version
+		}
+		return time.Since(e.Fetched) < p.schedulesTTL
+	})
+}
+
+// scheduleVersionsOf returns the versions of the stations' schedules, the md5s
+// being fetched when they were checked more than schedulesTTL ago. The
+// stations without md5 are missing.
+func (p *proxy) scheduleVersionsOf(stationIDs []string) map[string]string {
+	versions := make(map[string]string, len(stationIDs))
+	var stale []string
+
+	p.mu.Lock()
+	for _, id := range stationIDs {
+		if v, ok := p.scheduleVersions[id]; ok && time.Since(v.checked) < p.schedulesTTL {
+			versions[id] = v.version
+		} else {
+			stale = append(stale, id)
+		}
+	}
+	p.mu.Unlock()
+
+	if len(stale) == 0 {
+		return versions
+	}
+
+	fetched, err := p.fetchScheduleVersions(stale)
+	if err != nil {
+		log.Printf("schedules md5: %s", err)
+		return versions
+	}
+
+	now := time.Now()
+
+	p.mu.Lock()
+	for id, version := range fetched {
+		p.scheduleVersions[id] = scheduleVersion{version, now}
+		versions[id] = version
+	}
+	p.mu.Unlock()
+
+	return versions
+}
+
+// fetchScheduleVersions asks the md5s of the stations' schedules by day. The
+// stations the service gives an error for are left out.
+func (p *proxy) fetchScheduleVersions(stationIDs []string) (map[string]string, error) {
+	req := make([]struct {
+		StationID string `json:"stationID"`
+	}, len(stationIDs))
+	for i, id := range stationIDs {
+		req[i].StationID = id
+	}
+
+	payload, errMarshal := json.Marshal(req)
+	if errMarshal != nil {
+		return nil, errMarshal
+	}
+
+	status, body, err := p.fetch("POST", apiVersion+"/schedules/md5", payload)
+	if err != nil {
+		return nil, err
+	} else if status != http.StatusOK {
+		return nil, fmt.Errorf("resp.StatusCode != 200: %d", status)
+	}
+
+	var stations map[string]json.RawMessage
+	if err := json.Unmarshal(body, &stations); err != nil {
+		return nil, err
+	}
+
+	versions := make(map[string]string, len(stations))
+	for id, raw := range stations {
+		// an error is a code and a message instead of the days
+		var days map[string]scheduleMd5
+		if err := json.Unmarshal(raw, &days); err != nil || len(days) == 0 {
+			continue
+		}
+
+		var dates []string
+		for date, day := range days {
+			if day.Code != 0 {
+				continue
+			}
+			dates = append(dates, date+":"+day.Md5)
+		}
+		sort.Strings(dates)
+
+		versions[id] = strings.Join(dates, ",")
+	}
+
+	return versions, nil
+}
+
+// handleLines answers a POST {"request": [ids]} with one JSON object per
+// line. prepare, when not nil, is called with the ids before checking the
+// cache. parse returns the id and version of an upstream line, false for an
+// error which is returned but not cached.
+func (p *proxy) handleLines(w http.ResponseWriter, r *http.Request, path, prefix string, prepare func([]string), parse func([]byte) (string, string, bool), current func(entry) bool) {
+	if r.Method != "POST" {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	lines := make(map[string][]byte)
+	seen := make(map[string]bool, len(req.Request))
+	var ids, missing []string
+
+	for _, id := range req.Request {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	if prepare != nil {
+		prepare(ids)
+	}
+
+	for _, id := range ids {
+		if e, ok := p.cache.get(prefix + id); ok && current(e) {
+			lines[id] = e.Body
+		} else {
+			missing = append(missing, id)
+		}
+	}
+
+	var errorLines [][]byte
+
+	if len(missing) > 0 {
+		payload, errMarshal := json.Marshal(request{missing})
+		if errMarshal != nil {
+			http.Error(w, errMarshal.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		status, body, err := p.fetch("POST", apiVersion+path, payload)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		} else if status != http.StatusOK {
+			p.write(w, r, status, body)
+			return
+		}
+
+		for _, line := range bytes.Split(body, []byte("\n")) {
+			line = bytes.TrimSpace(line)
+			if len(line) == 0 {
+				continue
+			}
+
+			id, version, ok := parse(line)
+			if !ok {
+				errorLines = append(errorLines, line)
+				continue
+			}
+
+			lines[id] = line
+			if err := p.cache.put(entry{prefix + id, time.Now(), version, line}); err != nil {
+				log.Printf("cache %s: %s", prefix+id, err)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	for _, id := range ids {
+		if line, ok := lines[id]; ok {
+			buf.Write(line)
+			buf.WriteByte('\n')
+		}
+	}
+	for _, line := range errorLines {
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	p.write(w, r, http.StatusOK, buf.Bytes())
+}
+
+func (p *proxy) getUpstreamToken(renew bool) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !renew && p.upstreamToken != "" && time.Since(p.upstreamTokenTime) < upstreamTokenTTL {
+		return p.upstreamToken, nil
+	}
+
+	token, err := schedulesdirect.NewClientWithURL(p.upstream).GetToken(p.username, p.password)
+	if err != nil {
+		return "", err
+	}
+
+	p.upstreamToken = token
+	p.upstreamTokenTime = time.Now()
+
+	return token, nil
+}
+
+// fetch sends a request upstream with the proxy's token, renewed once when
+// refused, and returns the decoded body.
+func (p *proxy) fetch(method, uri string, payload []byte) (int, []byte, error) {
+	for attempt := 0; ; attempt++ {
+		token, errToken := p.getUpstreamToken(attempt > 0)
+		if errToken != nil {
+			return 0, nil, errToken
+		}
+
+		req, errNewRequest := http.NewRequest(method, p.upstream+uri, bytes.NewReader(payload))
+		if errNewRequest != nil {
+			return 0, nil, errNewRequest
+		}
+
+		req.Header.Add("User-Agent", "go-schedulesdirect")
+		req.Header.Add("token", token)
+		req.Header.Add("Accept-Encoding", "deflate")
+		if len(payload) > 0 {
+			req.Header.Add("Content-Type", "application/json")
+		}
+
+		resp, errDo := p.clientHttp.Do(req)
+		if errDo != nil {
+			return 0, nil, errDo
+		}
+
+		body, errRead := p.readBody(resp)
+		resp.Body.Close()
+		if errRead != nil {
+			return 0, nil, errRead
+		}
+
+		if resp.StatusCode == http.StatusForbidden && attempt == 0 {
+			continue
+		}
+
+		return resp.StatusCode, body, nil
+	}
+}
+
+// isZlib tells if a body starts with a zlib header, like the library does.
+func isZlib(header []byte) bool {
+	return len(header) >= 2 && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0
+}
+
+func (p *proxy) readBody(resp *http.Response) ([]byte, error) {
+	raw, errRead := ioutil.ReadAll(resp.Body)
+	if errRead != nil {
+		return nil, errRead
+	}
+
+	if resp.Header.Get("Content-Encoding") != "deflate" {
+		return raw, nil
+	}
+
+	p.mu.Lock()
+	p.deflate = true
+	p.mu.Unlock()
+
+	// "deflate" should be zlib but some servers send raw deflate
+	var reader io.ReadCloser
+	if isZlib(raw) {
+		zr, errZlib := zlib.NewReader(bytes.NewReader(raw))
+		if errZlib != nil {
+			return nil, errZlib
+		}
+		reader = zr
+	} else {
+		reader = flate.NewReader(bytes.NewReader(raw))
+	}
+	defer reader.Close()
+
+	return ioutil.ReadAll(reader)
+}
+
+// write sends a body deflate-encoded when the upstream does and the client
+// accepts it.
+func (p *proxy) write(w http.ResponseWriter, r *http.Request, status int, body []byte) {
+	p.mu.Lock()
+	deflate := p.deflate
+	p.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if deflate && strings.Contains(r.Header.Get("Accept-Encoding"), "deflate") {
+		w.Header().Set("Content-Encoding", "deflate")
+		w.WriteHeader(status)
+
+		zw := zlib.NewWriter(w)
+		zw.Write(body)
+		zw.Close()
+		return
+	}
+
+	w.WriteHeader(status)
+	w.Write(body)
+}
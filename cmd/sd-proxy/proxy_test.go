@@ -0,0 +1,278 @@
+package main
+
+import (
+	"compress/zlib"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	schedulesdirect "github.com/brunoqc/go-schedulesdirect"
+)
+
+// upstream is a fake service counting its requests by path.
+type upstream struct {
+	mu     sync.Mutex
+	hits   map[string]int
+	md5    string
+	server *httptest.Server
+}
+
+func newUpstream(t *testing.T) *upstream {
+	u := &upstream{hits: make(map[string]int), md5: "a"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/20131021/token", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			User string `json:"username"`
+			Pass string `json:"password"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.User != "user" || req.Pass != hashPassword("pass") {
+			t.Fatalf("credentials don't match: %v", req)
+		}
+		fmt.Fprint(w, `{"code":0,"message":"OK","serverID":"test","token":"upstream"}`)
+	})
+
+	handle := func(path string, h func(w http.ResponseWriter, r *http.Request)) {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("token") != "upstream" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+
+			u.mu.Lock()
+			u.hits[r.URL.Path]++
+			u.mu.Unlock()
+
+			h(w, r)
+		})
+	}
+
+	handle("/20131021/status", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"code":0,"lastDataUpdate":"2014-07-29T00:00:00Z","lineups":[{"ID":"USA-NY67791-X","modified":"2014-07-29T13:00:00Z","uri":"/20131021/lineups/USA-NY67791-X"}]}`)
+	})
+
+	handle("/20131021/lineups/USA-NY67791-X", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"map":[{"channel":"2","stationID":"10001"}],"metadata":{"lineup":"USA-NY67791-X","transport":"Cable"},"stations":[{"callsign":"WCBS","stationID":"10001"}]}`)
+	})
+
+	handle("/20131021/schedules", func(w http.ResponseWriter, r *http.Request) {
+		u.mu.Lock()
+		md5 := u.md5
+		u.mu.Unlock()
+
+		w.Header().Set("Content-Encoding", "deflate")
+		zw := zlib.NewWriter(w)
+		fmt.Fprintf(zw, `{"stationID":"10001","programs":[{"airDateTime":"2014-07-30T01:00:00Z","duration":1800,"md5":"%s","programID":"EP000000010001"}]}`+"\n", md5)
+		zw.Close()
+	})
+
+	handle("/20131021/schedules/md5", func(w http.ResponseWriter, r *http.Request) {
+		u.mu.Lock()
+		md5 := u.md5
+		u.mu.Unlock()
+
+		fmt.Fprintf(w, `{"10001":{"2014-07-30":{"code":0,"message":"OK","md5":"%s"}},"10002":{"code":2201,"message":"Invalid station."}}`, md5)
+	})
+
+	handle("/20131021/programs", func(w http.ResponseWriter, r *http.Request) {
+		var req request
+		json.NewDecoder(r.Body).Decode(&req)
+
+		u.mu.Lock()
+		md5 := u.md5
+		u.mu.Unlock()
+
+		for _, id := range req.Request {
+			fmt.Fprintf(w, `{"programID":"%s","md5":"%s","titles":{"title120":"Test"}}`+"\n", id, md5)
+		}
+	})
+
+	u.server = httptest.NewServer(mux)
+
+	return u
+}
+
+func (u *upstream) count(path string) int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	return u.hits[path]
+}
+
+func TestProxy(t *testing.T) {
+	u := newUpstream(t)
+	defer u.server.Close()
+
+	dir, errTemp := ioutil.TempDir("", "sd-proxy")
+	if errTemp != nil {
+		t.Fatal(errTemp)
+	}
+	defer os.RemoveAll(dir)
+
+	c, err := newCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := newProxy(u.server.URL, "user", "pass", c)
+	server := httptest.NewServer(p.handler())
+	defer server.Close()
+
+	client := schedulesdirect.NewClientWithURL(server.URL)
+
+	if _, err := client.GetToken("user", "wrong"); err == nil {
+		t.Fatal("err == nil")
+	}
+
+	token, err := client.GetToken("user", "pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != p.token {
+		t.Fatalf("token != p.token: %s", token)
+	}
+
+	if _, err := client.GetStatus("upstream"); err != schedulesdirect.Err_Forbidden {
+		t.Fatalf("err != Err_Forbidden: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		cm, err := client.GetChannelMapping(token, "/20131021/lineups/USA-NY67791-X")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cm.Stations) != 1 || cm.Stations[0].Callsign != "WCBS" {
+			t.Fatalf("cm.Stations doesn't match: %v", cm.Stations)
+		}
+
+		schedules, err := client.GetSchedules(token, []string{"10001"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(schedules) != 1 || schedules[0].StationID != "10001" {
+			t.Fatalf("schedules doesn't match: %v", schedules)
+		}
+
+		programs, err := client.GetProgramsInfo(token, []string{"EP000000010001"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(programs) != 1 || programs[0].Md5 != "a" {
+			t.Fatalf("programs doesn't match: %v", programs)
+		}
+	}
+
+	for path, expected := range map[string]int{
+		"/20131021/status":                1,
+		"/20131021/lineups/USA-NY67791-X": 1,
+		"/20131021/schedules":             1,
+		"/20131021/schedules/md5":         1,
+		"/20131021/programs":              1,
+	} {
+		if n := u.count(path); n != expected {
+			t.Fatalf("%s: %d != %d", path, n, expected)
+		}
+	}
+
+	// the md5s are checked again but the schedule didn't change
+	p.schedulesTTL = 0
+
+	if _, err := client.GetSchedules(token, []string{"10001"}); err != nil {
+		t.Fatal(err)
+	}
+	if n := u.count("/20131021/schedules"); n != 1 {
+		t.Fatalf("n != 1: %d", n)
+	}
+	if n := u.count("/20131021/schedules/md5"); n != 2 {
+		t.Fatalf("n != 2: %d", n)
+	}
+
+	// the new schedules give a new md5 for the program
+	u.mu.Lock()
+	u.md5 = "b"
+	u.mu.Unlock()
+
+	if _, err := client.GetSchedules(token, []string{"10001"}); err != nil {
+		t.Fatal(err)
+	}
+	if n := u.count("/20131021/schedules"); n != 2 {
+		t.Fatalf("n != 2: %d", n)
+	}
+
+	programs, err := client.GetProgramsInfo(token, []string{"EP000000010001"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(programs) != 1 || programs[0].Md5 != "b" {
+		t.Fatalf("programs doesn't match: %v", programs)
+	}
+	if n := u.count("/20131021/programs"); n != 2 {
+		t.Fatalf("n != 2: %d", n)
+	}
+
+	// the cache survives a restart
+	c2, err := newCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2 := newProxy(u.server.URL, "user", "pass", c2)
+	if md5 := p2.programMd5s["EP000000010001"]; md5 != "b" {
+		t.Fatalf("md5 != b: %s", md5)
+	}
+	if e, ok := c2.get("program/EP000000010001"); !ok || e.Version != "b" {
+		t.Fatalf("e doesn't match: %v", e)
+	}
+}
+
+func TestProxyProgramsWithoutSchedules(t *testing.T) {
+	u := newUpstream(t)
+	defer u.server.Close()
+
+	c, err := newCache("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// cached before a restart, no schedule gives its md5
+	c.put(entry{"program/EP000000010001", time.Now().Add(-time.Hour), "old", []byte(`{"programID":"EP000000010001","md5":"old"}`)})
+
+	p := newProxy(u.server.URL, "user", "pass", c)
+	server := httptest.NewServer(p.handler())
+	defer server.Close()
+
+	client := schedulesdirect.NewClientWithURL(server.URL)
+
+	token, err := client.GetToken("user", "pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	programs, err := client.GetProgramsInfo(token, []string{"EP000000010001"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(programs) != 1 || programs[0].Md5 != "old" {
+		t.Fatalf("programs doesn't match: %v", programs)
+	}
+
+	// fetched again once programsTTL is over
+	p.programsTTL = time.Minute
+
+	programs, err = client.GetProgramsInfo(token, []string{"EP000000010001"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(programs) != 1 || programs[0].Md5 != "a" {
+		t.Fatalf("programs doesn't match: %v", programs)
+	}
+	if n := u.count("/20131021/programs"); n != 1 {
+		t.Fatalf("n != 1: %d", n)
+	}
+}
@@ -0,0 +1,104 @@
+// sd-server serves a read API over a guide snapshot kept in a local store,
+// see /openapi.json. With a username and a password it syncs the snapshot
+// every interval, from the service or from a sd-proxy with -url.
+//
+//	sd-server -store /var/lib/sd-server/guide.json -username me -password secret
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	schedulesdirect "github.com/brunoqc/go-schedulesdirect"
+)
+
+const retryInterval = 10 * time.Minute
+
+func loadStore(name string) (schedulesdirect.Snapshot, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return schedulesdirect.Snapshot{}, err
+	}
+	defer f.Close()
+
+	return schedulesdirect.LoadSnapshot(f)
+}
+
+// saveStore writes a temporary file and renames it so the store is never
+// partially written.
+func saveStore(name string, s schedulesdirect.Snapshot) error {
+	tmp, errTemp := ioutil.TempFile(filepath.Dir(name), ".store-")
+	if errTemp != nil {
+		return errTemp
+	}
+	defer os.Remove(tmp.Name())
+
+	errSave := s.Save(tmp)
+	errClose := tmp.Close()
+	if errSave != nil {
+		return errSave
+	} else if errClose != nil {
+		return errClose
+	}
+
+	return os.Rename(tmp.Name(), name)
+}
+
+func main() {
+	listen := flag.String("listen", ":8081", "address to listen on")
+	store := flag.String("store", "guide.json", "snapshot file")
+	baseURL := flag.String("url", "https://json.schedulesdirect.org", "Schedules Direct or sd-proxy URL")
+	username := flag.String("username", "", "username, no sync when empty")
+	password := flag.String("password", "", "password")
+	interval := flag.Duration("interval", 12*time.Hour, "time between syncs")
+	cors := flag.String("cors", "*", "allowed origin for CORS, disabled when empty")
+	flag.Parse()
+
+	snapshot, err := loadStore(*store)
+	if err != nil && !os.IsNotExist(err) {
+		log.Fatal(err)
+	}
+
+	s := newServer(snapshot, *cors)
+
+	if *username != "" {
+		client := schedulesdirect.NewClientWithURL(*baseURL)
+
+		go func() {
+			next := s.current().snapshot.Time.Add(*interval)
+
+			for {
+				if !time.Now().Before(next) {
+					log.Print("syncing")
+
+					// retried sooner on errors
+					next = time.Now().Add(retryInterval)
+
+					token, errToken := client.GetToken(*username, *password)
+					if errToken != nil {
+						log.Printf("token: %s", errToken)
+					} else if snapshot, errSync := client.Sync(token); errSync != nil {
+						log.Printf("sync: %s", errSync)
+					} else {
+						s.update(snapshot)
+						next = snapshot.Time.Add(*interval)
+
+						if err := saveStore(*store, snapshot); err != nil {
+							log.Printf("store: %s", err)
+						}
+					}
+				}
+
+				time.Sleep(time.Minute)
+			}
+		}()
+	}
+
+	log.Printf("listening on %s", *listen)
+	log.Fatal(http.ListenAndServe(*listen, s.handler()))
+}
@@ -0,0 +1,214 @@
+package main
+
+// openAPI describes the API, served at /openapi.json.
+const openAPI = `{
+  "openapi": "3.0.3",
+  "info": {
+    "title": "sd-server",
+    "description": "Read API over a Schedules Direct guide snapshot. Lists are paginated with limit and offset, responses have an ETag.",
+    "version": "1.0.0"
+  },
+  "paths": {
+    "/channels": {
+      "get": {
+        "summary": "Channels of the lineups, in channel order",
+        "parameters": [
+          {"$ref": "#/components/parameters/limit"},
+          {"$ref": "#/components/parameters/offset"}
+        ],
+        "responses": {
+          "200": {"description": "Channels", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/StationPage"}}}},
+          "400": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/channels/{stationID}/airings": {
+      "get": {
+        "summary": "Airings of a channel overlapping [from, to)",
+        "parameters": [
+          {"name": "stationID", "in": "path", "required": true, "schema": {"type": "string"}},
+          {"name": "from", "in": "query", "description": "RFC 3339, now by default", "schema": {"type": "string", "format": "date-time"}},
+          {"name": "to", "in": "query", "description": "RFC 3339, 24 hours after from by default", "schema": {"type": "string", "format": "date-time"}},
+          {"$ref": "#/components/parameters/limit"},
+          {"$ref": "#/components/parameters/offset"}
+        ],
+        "responses": {
+          "200": {"description": "Airings", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/AiringPage"}}}},
+          "400": {"$ref": "#/components/responses/Error"},
+          "404": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/programs/{programID}": {
+      "get": {
+        "summary": "Details of a program",
+        "parameters": [
+          {"name": "programID", "in": "path", "required": true, "schema": {"type": "string"}}
+        ],
+        "responses": {
+          "200": {"description": "Program", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Program"}}}},
+          "404": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/search": {
+      "get": {
+        "summary": "Programs with q in a title or a description",
+        "parameters": [
+          {"name": "q", "in": "query", "required": true, "schema": {"type": "string"}},
+          {"$ref": "#/components/parameters/limit"},
+          {"$ref": "#/components/parameters/offset"}
+        ],
+        "responses": {
+          "200": {"description": "Programs sorted by title", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ProgramPage"}}}},
+          "400": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/now": {
+      "get": {
+        "summary": "Airing on now for each channel",
+        "parameters": [
+          {"$ref": "#/components/parameters/limit"},
+          {"$ref": "#/components/parameters/offset"}
+        ],
+        "responses": {
+          "200": {"description": "Channels with their airing, null when none", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/NowPage"}}}},
+          "400": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/grid": {
+      "get": {
+        "summary": "Grid of a lineup",
+        "parameters": [
+          {"name": "lineup", "in": "query", "description": "the first lineup by default", "schema": {"type": "string"}},
+          {"name": "start", "in": "query", "description": "RFC 3339, now rounded down to the slot by default", "schema": {"type": "string", "format": "date-time"}},
+          {"name": "slots", "in": "query", "schema": {"type": "integer", "minimum": 1, "maximum": 48, "default": 6}},
+          {"name": "slot", "in": "query", "description": "minutes", "schema": {"type": "integer", "minimum": 1, "default": 30}}
+        ],
+        "responses": {
+          "200": {"description": "Grid", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Grid"}}}},
+          "400": {"$ref": "#/components/responses/Error"},
+          "404": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    }
+  },
+  "components": {
+    "parameters": {
+      "limit": {"name": "limit", "in": "query", "schema": {"type": "integer", "minimum": 1, "maximum": 1000, "default": 100}},
+      "offset": {"name": "offset", "in": "query", "schema": {"type": "integer", "minimum": 0, "default": 0}}
+    },
+    "responses": {
+      "Error": {"description": "Error", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}}
+    },
+    "schemas": {
+      "Error": {
+        "type": "object",
+        "properties": {"code": {"type": "integer"}, "message": {"type": "string"}}
+      },
+      "Channel": {
+        "type": "object",
+        "properties": {"lineup": {"type": "string"}, "transport": {"type": "string"}, "channel": {"type": "string"}}
+      },
+      "Station": {
+        "type": "object",
+        "properties": {
+          "stationID": {"type": "string"},
+          "callsign": {"type": "string"},
+          "name": {"type": "string"},
+          "affiliate": {"type": "string"},
+          "logo": {"type": "string"},
+          "channels": {"type": "array", "items": {"$ref": "#/components/schemas/Channel"}}
+        }
+      },
+      "Airing": {
+        "type": "object",
+        "properties": {
+          "stationID": {"type": "string"},
+          "programID": {"type": "string"},
+          "start": {"type": "string", "format": "date-time"},
+          "end": {"type": "string", "format": "date-time"},
+          "duration": {"type": "integer", "description": "seconds"},
+          "title": {"type": "string"},
+          "episode": {"type": "string", "description": "like S03E07"},
+          "new": {"type": "boolean"},
+          "live": {"type": "boolean"},
+          "hd": {"type": "boolean"},
+          "ratings": {"type": "array", "items": {"type": "string"}}
+        }
+      },
+      "Credit": {
+        "type": "object",
+        "properties": {"name": {"type": "string"}, "role": {"type": "string"}, "character": {"type": "string"}}
+      },
+      "Program": {
+        "type": "object",
+        "properties": {
+          "programID": {"type": "string"},
+          "title": {"type": "string"},
+          "description": {"type": "string"},
+          "showType": {"type": "string"},
+          "genres": {"type": "array", "items": {"type": "string"}},
+          "originalAirDate": {"type": "string"},
+          "episode": {"type": "string"},
+          "year": {"type": "string"},
+          "credits": {"type": "array", "items": {"$ref": "#/components/schemas/Credit"}}
+        }
+      },
+      "Now": {
+        "type": "object",
+        "properties": {
+          "station": {"$ref": "#/components/schemas/Station"},
+          "airing": {"allOf": [{"$ref": "#/components/schemas/Airing"}], "nullable": true}
+        }
+      },
+      "GridCell": {
+        "type": "object",
+        "properties": {
+          "column": {"type": "integer"},
+          "span": {"type": "integer"},
+          "start": {"type": "string", "format": "date-time"},
+          "end": {"type": "string", "format": "date-time"},
+          "programID": {"type": "string"},
+          "title": {"type": "string"},
+          "new": {"type": "boolean"},
+          "live": {"type": "boolean"},
+          "hd": {"type": "boolean"},
+          "clippedStart": {"type": "boolean"},
+          "clippedEnd": {"type": "boolean"},
+          "noData": {"type": "boolean"}
+        }
+      },
+      "GridRow": {
+        "type": "object",
+        "properties": {
+          "channel": {"type": "string"},
+          "stationID": {"type": "string"},
+          "callsign": {"type": "string"},
+          "name": {"type": "string"},
+          "cells": {"type": "array", "items": {"$ref": "#/components/schemas/GridCell"}}
+        }
+      },
+      "Grid": {
+        "type": "object",
+        "properties": {
+          "start": {"type": "string", "format": "date-time"},
+          "end": {"type": "string", "format": "date-time"},
+          "slotDuration": {"type": "integer", "description": "seconds"},
+          "slots": {"type": "array", "items": {"type": "string", "format": "date-time"}},
+          "rows": {"type": "array", "items": {"$ref": "#/components/schemas/GridRow"}}
+        }
+      },
+      "StationPage": {"allOf": [{"$ref": "#/components/schemas/Page"}, {"properties": {"items": {"type": "array", "items": {"$ref": "#/components/schemas/Station"}}}}]},
+      "AiringPage": {"allOf": [{"$ref": "#/components/schemas/Page"}, {"properties": {"items": {"type": "array", "items": {"$ref": "#/components/schemas/Airing"}}}}]},
+      "ProgramPage": {"allOf": [{"$ref": "#/components/schemas/Page"}, {"properties": {"items": {"type": "array", "items": {"$ref": "#/components/schemas/Program"}}}}]},
+      "NowPage": {"allOf": [{"$ref": "#/components/schemas/Page"}, {"properties": {"items": {"type": "array", "items": {"$ref": "#/components/schemas/Now"}}}}]},
+      "Page": {
+        "type": "object",
+        "properties": {"total": {"type": "integer"}, "offset": {"type": "integer"}, "limit": {"type": "integer"}}
+      }
+    }
+  }
+}`
@@ -0,0 +1,556 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	schedulesdirect "github.com/brunoqc/go-schedulesdirect"
+)
+
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+
+	defaultGridSlots = 6
+	maxGridSlots     = 48
+)
+
+// guideData is a snapshot with its indexes, replaced as a whole after a sync.
+type guideData struct {
+	snapshot schedulesdirect.Snapshot
+	guide    *schedulesdirect.Guide
+	stations *schedulesdirect.StationIndex
+	lineups  []*schedulesdirect.Lineup
+
+	// stationIDs in the order of the lineups and their channels
+	stationIDs []string
+}
+
+func newGuideData(s schedulesdirect.Snapshot) *guideData {
+	d := &guideData{
+		snapshot: s,
+		guide:    s.Guide(),
+		stations: s.Stations(),
+		lineups:  s.Lineups(),
+	}
+
+	seen := make(map[string]bool)
+	for _, l := range d.lineups {
+		for _, ls := range l.Stations {
+			if !seen[ls.StationID] {
+				seen[ls.StationID] = true
+				d.stationIDs = append(d.stationIDs, ls.StationID)
+			}
+		}
+	}
+
+	return d
+}
+
+type server struct {
+	// allowed origin for CORS, disabled when empty
+	cors string
+	now  func() time.Time
+
+	mu   sync.RWMutex
+	data *guideData
+}
+
+func newServer(s schedulesdirect.Snapshot, cors string) *server {
+	return &server{cors: cors, now: time.Now, data: newGuideData(s)}
+}
+
+func (s *server) update(snapshot schedulesdirect.Snapshot) {
+	d := newGuideData(snapshot)
+
+	s.mu.Lock()
+	s.data = d
+	s.mu.Unlock()
+}
+
+func (s *server) current() *guideData {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.data
+}
+
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/channels", s.get(s.handleChannels))
+	mux.HandleFunc("/channels/", s.get(s.handleChannelAirings))
+	mux.HandleFunc("/programs/", s.get(s.handleProgram))
+	mux.HandleFunc("/search", s.get(s.handleSearch))
+	mux.HandleFunc("/now", s.get(s.handleNow))
+	mux.HandleFunc("/grid", s.get(s.handleGrid))
+	mux.HandleFunc("/openapi.json", s.get(s.handleOpenAPI))
+	return mux
+}
+
+type apiError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// get handles CORS and only lets GET requests through, the handler returns
+// the status and the value to send as JSON.
+func (s *server) get(h func(r *http.Request) (int, interface{})) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.cors != "" {
+			w.Header().Set("Access-Control-Allow-Origin", s.cors)
+			w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "If-None-Match")
+			w.Header().Set("Access-Control-Expose-Headers", "ETag")
+		}
+
+		switch r.Method {
+		case "OPTIONS":
+			w.WriteHeader(http.StatusNoContent)
+			return
+		case "GET", "HEAD":
+		default:
+			writeJSON(w, r, http.StatusMethodNotAllowed, apiError{http.StatusMethodNotAllowed, "method not allowed"})
+			return
+		}
+
+		status, v := h(r)
+		writeJSON(w, r, status, v)
+	}
+}
+
+// writeJSON sends v with an ETag of its content, a 304 when it matches
+// If-None-Match.
+func writeJSON(w http.ResponseWriter, r *http.Request, status int, v interface{}) {
+	body, errMarshal := json.Marshal(v)
+	if errMarshal != nil {
+		status = http.StatusInternalServerError
+		body, _ = json.Marshal(apiError{status, errMarshal.Error()})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if status == http.StatusOK {
+		h := sha1.Sum(body)
+		etag := `"` + hex.EncodeToString(h[:]) + `"`
+		w.Header().Set("ETag", etag)
+
+		for _, match := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+			if m := strings.TrimSpace(match); m == etag || m == "*" {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+	}
+
+	w.WriteHeader(status)
+	if r.Method != "HEAD" {
+		w.Write(body)
+		w.Write([]byte("\n"))
+	}
+}
+
+func badRequest(message string) (int, interface{}) {
+	return http.StatusBadRequest, apiError{http.StatusBadRequest, message}
+}
+
+func notFound(message string) (int, interface{}) {
+	return http.StatusNotFound, apiError{http.StatusNotFound, message}
+}
+
+// page is a paginated list.
+type page struct {
+	Total  int         `json:"total"`
+	Offset int         `json:"offset"`
+	Limit  int         `json:"limit"`
+	Items  interface{} `json:"items"`
+}
+
+// pagination parses limit and offset.
+func pagination(r *http.Request) (int, int, bool) {
+	limit, offset := defaultLimit, 0
+
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return 0, 0, false
+		}
+		limit = n
+		if limit > maxLimit {
+			limit = maxLimit
+		}
+	}
+
+	if v := r.URL.Query().Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return 0, 0, false
+		}
+		offset = n
+	}
+
+	return limit, offset, true
+}
+
+// bounds returns the slice bounds of a page of a list of n items.
+func bounds(n, limit, offset int) (int, int) {
+	if offset > n {
+		offset = n
+	}
+	end := offset + limit
+	if end > n {
+		end = n
+	}
+	return offset, end
+}
+
+// parseTime parses a RFC 3339 time, def when empty.
+func parseTime(r *http.Request, name string, def time.Time) (time.Time, bool) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, true
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	return t, err == nil
+}
+
+type channelJSON struct {
+	Lineup    string `json:"lineup"`
+	Transport string `json:"transport"`
+	Channel   string `json:"channel"`
+}
+
+type stationJSON struct {
+	StationID string        `json:"stationID"`
+	Callsign  string        `json:"callsign,omitempty"`
+	Name      string        `json:"name,omitempty"`
+	Affiliate string        `json:"affiliate,omitempty"`
+	Logo      string        `json:"logo,omitempty"`
+	Channels  []channelJSON `json:"channels"`
+}
+
+func (d *guideData) station(stationID string) stationJSON {
+	sj := stationJSON{StationID: stationID, Channels: []channelJSON{}}
+
+	for _, l := range d.lineups {
+		if ls, ok := l.Station(stationID); ok && sj.Callsign == "" {
+			sj.Callsign = ls.Callsign
+			sj.Name = ls.Name
+			sj.Affiliate = ls.Affiliate
+			sj.Logo = ls.Logo.URL
+		}
+	}
+
+	for _, sc := range d.stations.Channels(stationID) {
+		sj.Channels = append(sj.Channels, channelJSON{sc.Lineup, sc.Transport, sc.Channel})
+	}
+
+	return sj
+}
+
+type airingJSON struct {
+	StationID string    `json:"stationID"`
+	ProgramID string    `json:"programID"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Duration  int       `json:"duration"` // seconds
+	Title     string    `json:"title,omitempty"`
+	Episode   string    `json:"episode,omitempty"`
+	New       bool      `json:"new,omitempty"`
+	Live      bool      `json:"live,omitempty"`
+	HD        bool      `json:"hd,omitempty"`
+	Ratings   []string  `json:"ratings,omitempty"`
+}
+
+func newAiringJSON(a *schedulesdirect.Airing) airingJSON {
+	aj := airingJSON{
+		StationID: a.StationID,
+		ProgramID: a.ProgramID,
+		Start:     a.Start(),
+		End:       a.End(),
+		Duration:  a.Duration,
+		New:       a.IsNew(),
+		Live:      a.IsLive(),
+		HD:        a.IsHD(),
+	}
+
+	for _, rating := range a.Ratings() {
+		aj.Ratings = append(aj.Ratings, rating.Body+" "+rating.Code)
+	}
+
+	if a.Program != nil {
+		if t, ok := (schedulesdirect.TextPreference{}).Title(*a.Program); ok {
+			aj.Title = t.Text
+		}
+		if e, ok := a.Program.EpisodeNumbers(); ok {
+			aj.Episode = e.SxxEyy()
+		}
+	}
+
+	return aj
+}
+
+func airingsJSON(airings []*schedulesdirect.Airing) []airingJSON {
+	list := make([]airingJSON, len(airings))
+	for i, a := range airings {
+		list[i] = newAiringJSON(a)
+	}
+	return list
+}
+
+type creditJSON struct {
+	Name      string `json:"name"`
+	Role      string `json:"role"`
+	Character string `json:"character,omitempty"`
+}
+
+type programJSON struct {
+	ProgramID       string       `json:"programID"`
+	Title           string       `json:"title"`
+	Description     string       `json:"description,omitempty"`
+	ShowType        string       `json:"showType,omitempty"`
+	Genres          []string     `json:"genres"`
+	OriginalAirDate string       `json:"originalAirDate,omitempty"`
+	Episode         string       `json:"episode,omitempty"`
+	Year            string       `json:"year,omitempty"`
+	Credits         []creditJSON `json:"credits"`
+}
+
+func (d *guideData) program(programID string) (programJSON, bool) {
+	p, ok := d.guide.Program(programID)
+	if !ok {
+		return programJSON{}, false
+	}
+
+	pj := programJSON{
+		ProgramID:       p.ProgramID,
+		ShowType:        p.ShowType,
+		Genres:          append([]string{}, p.Genres...),
+		OriginalAirDate: p.OriginalAirDate,
+		Year:            p.Movie.Year,
+		Credits:         []creditJSON{},
+	}
+
+	var tp schedulesdirect.TextPreference
+	if t, ok := tp.Title(*p); ok {
+		pj.Title = t.Text
+	}
+	if t, ok := tp.Description(*p); ok {
+		pj.Description = t.Text
+	}
+	if e, ok := p.EpisodeNumbers(); ok {
+		pj.Episode = e.SxxEyy()
+	}
+
+	for _, c := range p.Credits() {
+		pj.Credits = append(pj.Credits, creditJSON{c.Name, c.Role, c.CharacterName})
+	}
+
+	return pj, true
+}
+
+func (s *server) handleChannels(r *http.Request) (int, interface{}) {
+	limit, offset, ok := pagination(r)
+	if !ok {
+		return badRequest("invalid limit or offset")
+	}
+
+	d := s.current()
+
+	from, to := bounds(len(d.stationIDs), limit, offset)
+	items := []stationJSON{}
+	for _, id := range d.stationIDs[from:to] {
+		items = append(items, d.station(id))
+	}
+
+	return http.StatusOK, page{len(d.stationIDs), offset, limit, items}
+}
+
+// handleChannelAirings serves /channels/{stationID}/airings?from&to, from
+// defaults to now and to to 24 hours after from.
+func (s *server) handleChannelAirings(r *http.Request) (int, interface{}) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/channels/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] != "airings" {
+		return notFound("not found")
+	}
+	stationID := parts[0]
+
+	d := s.current()
+
+	if !containsString(d.stationIDs, stationID) {
+		return notFound("channel not found: " + stationID)
+	}
+
+	from, okFrom := parseTime(r, "from", s.now())
+	to, okTo := parseTime(r, "to", from.Add(24*time.Hour))
+	if !okFrom || !okTo || !to.After(from) {
+		return badRequest("invalid from or to")
+	}
+
+	limit, offset, ok := pagination(r)
+	if !ok {
+		return badRequest("invalid limit or offset")
+	}
+
+	airings := d.guide.Between(stationID, from, to)
+	start, end := bounds(len(airings), limit, offset)
+
+	return http.StatusOK, page{len(airings), offset, limit, airingsJSON(airings[start:end])}
+}
+
+func (s *server) handleProgram(r *http.Request) (int, interface{}) {
+	programID := strings.TrimPrefix(r.URL.Path, "/programs/")
+
+	pj, ok := s.current().program(programID)
+	if !ok {
+		return notFound("program not found: " + programID)
+	}
+
+	return http.StatusOK, pj
+}
+
+// handleSearch finds the programs with q in a title or a description, case
+// insensitive.
+func (s *server) handleSearch(r *http.Request) (int, interface{}) {
+	q := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("q")))
+	if q == "" {
+		return badRequest("q is required")
+	}
+
+	limit, offset, ok := pagination(r)
+	if !ok {
+		return badRequest("invalid limit or offset")
+	}
+
+	d := s.current()
+
+	var matches []programJSON
+	for _, p := range d.snapshot.Programs {
+		found := false
+		for _, v := range append(p.TitleVariants(), p.DescriptionVariants()...) {
+			if strings.Contains(strings.ToLower(v.Text), q) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			continue
+		}
+
+		if pj, ok := d.program(p.ProgramID); ok {
+			matches = append(matches, pj)
+		}
+	}
+
+	sort.Sort(programsByTitle(matches))
+
+	start, end := bounds(len(matches), limit, offset)
+	items := append([]programJSON{}, matches[start:end]...)
+
+	return http.StatusOK, page{len(matches), offset, limit, items}
+}
+
+type nowJSON struct {
+	Station stationJSON `json:"station"`
+	Airing  *airingJSON `json:"airing"`
+}
+
+// handleNow returns the airing on now for each channel, null when none.
+func (s *server) handleNow(r *http.Request) (int, interface{}) {
+	limit, offset, ok := pagination(r)
+	if !ok {
+		return badRequest("invalid limit or offset")
+	}
+
+	d := s.current()
+
+	from, to := bounds(len(d.stationIDs), limit, offset)
+	ids := d.stationIDs[from:to]
+
+	on := d.guide.AtAll(ids, s.now())
+
+	items := []nowJSON{}
+	for _, id := range ids {
+		n := nowJSON{Station: d.station(id)}
+		if a, ok := on[id]; ok {
+			aj := newAiringJSON(a)
+			n.Airing = &aj
+		}
+		items = append(items, n)
+	}
+
+	return http.StatusOK, page{len(d.stationIDs), offset, limit, items}
+}
+
+// handleGrid serves /grid?lineup&start&slots&slot, lineup defaults to the
+// first one, start to now rounded down to the slot.
+func (s *server) handleGrid(r *http.Request) (int, interface{}) {
+	d := s.current()
+
+	lineup := r.URL.Query().Get("lineup")
+	if lineup == "" && len(d.lineups) > 0 {
+		lineup = d.lineups[0].Lineup
+	}
+
+	slot := schedulesdirect.DefaultGridSlot
+	if v := r.URL.Query().Get("slot"); v != "" {
+		minutes, err := strconv.Atoi(v)
+		if err != nil || minutes <= 0 {
+			return badRequest("invalid slot")
+		}
+		slot = time.Duration(minutes) * time.Minute
+	}
+
+	start, ok := parseTime(r, "start", s.now().Truncate(slot))
+	if !ok {
+		return badRequest("invalid start")
+	}
+
+	slots := defaultGridSlots
+	if v := r.URL.Query().Get("slots"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > maxGridSlots {
+			return badRequest("invalid slots")
+		}
+		slots = n
+	}
+
+	grid, found, err := d.snapshot.Grid(lineup, schedulesdirect.GridOptions{Start: start, Slots: slots, SlotDuration: slot})
+	if !found {
+		return notFound("lineup not found: " + lineup)
+	} else if err != nil {
+		return badRequest(err.Error())
+	}
+
+	return http.StatusOK, grid
+}
+
+func (s *server) handleOpenAPI(r *http.Request) (int, interface{}) {
+	return http.StatusOK, json.RawMessage(openAPI)
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+type programsByTitle []programJSON
+
+func (p programsByTitle) Len() int      { return len(p) }
+func (p programsByTitle) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p programsByTitle) Less(i, j int) bool {
+	if p[i].Title != p[j].Title {
+		return p[i].Title < p[j].Title
+	}
+	return p[i].ProgramID < p[j].ProgramID
+}
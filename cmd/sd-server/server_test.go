@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	schedulesdirect "github.com/brunoqc/go-schedulesdirect"
+)
+
+const testSnapshot = `{"time":"2014-07-30T00:00:00Z",
+"channelMappings":[{"map":[{"channel":"2","stationID":"10001"},{"channel":"3","stationID":"10002"}],"metadata":{"lineup":"USA-NY67791-X","transport":"Cable"},"stations":[{"callsign":"WCBS","name":"CBS","stationID":"10001"},{"callsign":"WNBC","name":"NBC","stationID":"10002"}]}],
+"schedules":[{"stationID":"10001","programs":[{"airDateTime":"2014-07-30T01:00:00Z","duration":1800,"programID":"EP000000010001","new":true},{"airDateTime":"2014-07-30T01:30:00Z","duration":1800,"programID":"MV000000020000"}]},
+{"stationID":"10002","programs":[{"airDateTime":"2014-07-30T02:00:00Z","duration":3600,"programID":"EP000000010001"}]}],
+"programs":[{"programID":"EP000000010001","titles":{"title120":"The Show"},"descriptions":{"description100":[{"description":"A detective story.","descriptionLanguage":"en"}]},"genres":["Drama"],"metadata":[{"Gracenote":{"season":2,"episode":5}}],"cast":[{"name":"Jane Doe","role":"Actor","characterName":"Detective","billingOrder":"01"}]},
+{"programID":"MV000000020000","titles":{"title120":"A Movie"},"movie":{"year":"1999"}}]}`
+
+func testServer(t *testing.T) *server {
+	snapshot, err := schedulesdirect.LoadSnapshot(strings.NewReader(testSnapshot))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := newServer(snapshot, "*")
+	s.now = func() time.Time {
+		return time.Date(2014, 7, 30, 1, 15, 0, 0, time.UTC)
+	}
+
+	return s
+}
+
+func testGet(t *testing.T, s *server, url string, expectedStatus int, v interface{}) *httptest.ResponseRecorder {
+	r, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	s.handler().ServeHTTP(w, r)
+
+	if w.Code != expectedStatus {
+		t.Fatalf("%s: w.Code != %d: %d %s", url, expectedStatus, w.Code, w.Body)
+	}
+
+	if v != nil {
+		if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return w
+}
+
+type testPage struct {
+	Total  int             `json:"total"`
+	Offset int             `json:"offset"`
+	Limit  int             `json:"limit"`
+	Items  json.RawMessage `json:"items"`
+}
+
+func TestChannels(t *testing.T) {
+	s := testServer(t)
+
+	var p testPage
+	testGet(t, s, "/channels?limit=1&offset=1", http.StatusOK, &p)
+
+	var stations []stationJSON
+	if err := json.Unmarshal(p.Items, &stations); err != nil {
+		t.Fatal(err)
+	}
+
+	if p.Total != 2 || p.Limit != 1 || p.Offset != 1 || len(stations) != 1 {
+		t.Fatalf("p doesn't match: %v", p)
+	}
+	if stations[0].StationID != "10002" || stations[0].Callsign != "WNBC" || len(stations[0].Channels) != 1 || stations[0].Channels[0].Channel != "3" {
+		t.Fatalf("stations[0] doesn't match: %v", stations[0])
+	}
+
+	testGet(t, s, "/channels?limit=x", http.StatusBadRequest, nil)
+}
+
+func TestChannelAirings(t *testing.T) {
+	s := testServer(t)
+
+	var p testPage
+	testGet(t, s, "/channels/10001/airings?from=2014-07-30T00:00:00Z&to=2014-07-30T02:00:00Z", http.StatusOK, &p)
+
+	var airings []airingJSON
+	if err := json.Unmarshal(p.Items, &airings); err != nil {
+		t.Fatal(err)
+	}
+
+	if p.Total != 2 || len(airings) != 2 {
+		t.Fatalf("p doesn't match: %v", p)
+	}
+	if airings[0].Title != "The Show" || airings[0].Episode != "S02E05" || !airings[0].New {
+		t.Fatalf("airings[0] doesn't match: %v", airings[0])
+	}
+
+	// from defaults to now
+	testGet(t, s, "/channels/10001/airings", http.StatusOK, &p)
+	if p.Total != 2 {
+		t.Fatalf("p.Total != 2: %d", p.Total)
+	}
+
+	testGet(t, s, "/channels/99999/airings", http.StatusNotFound, nil)
+	testGet(t, s, "/channels/10001/airings?from=yesterday", http.StatusBadRequest, nil)
+}
+
+func TestProgram(t *testing.T) {
+	s := testServer(t)
+
+	var pj programJSON
+	testGet(t, s, "/programs/EP000000010001", http.StatusOK, &pj)
+
+	if pj.Title != "The Show" || pj.Description != "A detective story." || len(pj.Credits) != 1 || pj.Credits[0].Character != "Detective" {
+		t.Fatalf("pj doesn't match: %v", pj)
+	}
+
+	testGet(t, s, "/programs/EP000000019999", http.StatusNotFound, nil)
+}
+
+func TestSearch(t *testing.T) {
+	s := testServer(t)
+
+	var p testPage
+	testGet(t, s, "/search?q=DETECTIVE", http.StatusOK, &p)
+
+	var programs []programJSON
+	if err := json.Unmarshal(p.Items, &programs); err != nil {
+		t.Fatal(err)
+	}
+	if p.Total != 1 || programs[0].ProgramID != "EP000000010001" {
+		t.Fatalf("programs doesn't match: %v", programs)
+	}
+
+	testGet(t, s, "/search", http.StatusBadRequest, nil)
+}
+
+func TestNow(t *testing.T) {
+	s := testServer(t)
+
+	var p testPage
+	testGet(t, s, "/now", http.StatusOK, &p)
+
+	var now []nowJSON
+	if err := json.Unmarshal(p.Items, &now); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(now) != 2 || now[0].Airing == nil || now[0].Airing.ProgramID != "EP000000010001" || now[1].Airing != nil {
+		t.Fatalf("now doesn't match: %v", now)
+	}
+}
+
+func TestGrid(t *testing.T) {
+	s := testServer(t)
+
+	var g schedulesdirect.Grid
+	testGet(t, s, "/grid?slots=4", http.StatusOK, &g)
+
+	if !g.Start.Equal(time.Date(2014, 7, 30, 1, 0, 0, 0, time.UTC)) || len(g.Slots) != 4 || len(g.Rows) != 2 {
+		t.Fatalf("g doesn't match: %v", g)
+	}
+
+	testGet(t, s, "/grid?lineup=USA-OTA-10001", http.StatusNotFound, nil)
+	testGet(t, s, "/grid?slots=100", http.StatusBadRequest, nil)
+}
+
+func TestETagAndCORS(t *testing.T) {
+	s := testServer(t)
+
+	w := testGet(t, s, "/openapi.json", http.StatusOK, nil)
+
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("etag is empty")
+	}
+	if w.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Fatalf("Access-Control-Allow-Origin doesn't match: %s", w.Header().Get("Access-Control-Allow-Origin"))
+	}
+
+	r, _ := http.NewRequest("GET", "/openapi.json", nil)
+	r.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	s.handler().ServeHTTP(w, r)
+	if w.Code != http.StatusNotModified {
+		t.Fatalf("w.Code != 304: %d", w.Code)
+	}
+
+	r, _ = http.NewRequest("OPTIONS", "/channels", nil)
+	w = httptest.NewRecorder()
+	s.handler().ServeHTTP(w, r)
+	if w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Methods") == "" {
+		t.Fatalf("preflight doesn't match: %d %v", w.Code, w.Header())
+	}
+
+	r, _ = http.NewRequest("POST", "/channels", nil)
+	w = httptest.NewRecorder()
+	s.handler().ServeHTTP(w, r)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("w.Code != 405: %d", w.Code)
+	}
+}
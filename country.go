@@ -0,0 +1,556 @@
+package schedulesdirect
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type country struct {
+	Alpha2 string
+	Alpha3 string
+	Name   string
+}
+
+// CountryError is returned when a country can't be matched to an ISO-3166-1 entry.
+type CountryError struct {
+	Country string
+}
+
+func (e CountryError) Error() string {
+	return fmt.Sprintf("unknown country: %q", e.Country)
+}
+
+// PostalCodeError is returned when a postal code isn't valid for its country.
+type PostalCodeError struct {
+	Country    string
+	PostalCode string
+}
+
+func (e PostalCodeError) Error() string {
+	return fmt.Sprintf("invalid postal code for %s: %q", e.Country, e.PostalCode)
+}
+
+// upper-cased alpha-2, alpha-3, names and aliases to alpha-3
+var countryIndex = make(map[string]string)
+
+func init() {
+	for _, c := range countries {
+		countryIndex[c.Alpha2] = c.Alpha3
+		countryIndex[c.Alpha3] = c.Alpha3
+		countryIndex[strings.ToUpper(c.Name)] = c.Alpha3
+	}
+
+	for name, alpha3 := range countryAliases {
+		countryIndex[strings.ToUpper(name)] = alpha3
+	}
+}
+
+// NormalizeCountry accepts an ISO-3166-1 alpha-2 or alpha-3 code or an English
+// country name and returns the alpha-3 code expected by the service.
+func NormalizeCountry(country string) (string, error) {
+	alpha3, ok := countryIndex[strings.ToUpper(strings.TrimSpace(country))]
+	if !ok {
+		return "", CountryError{country}
+	}
+
+	return alpha3, nil
+}
+
+var postalCodeValidators = map[string]*regexp.Regexp{
+	"USA": regexp.MustCompile(`^[0-9]{5}(-?[0-9]{4})?$`),
+	"CAN": regexp.MustCompile(`^[ABCEGHJ-NPRSTVXY][0-9][ABCEGHJ-NPRSTV-Z][0-9][ABCEGHJ-NPRSTV-Z][0-9]$`),
+	"GBR": regexp.MustCompile(`^(GIR0AA|[A-PR-UWYZ]([0-9]{1,2}|[A-HK-Y][0-9]{1,2}|[0-9][A-HJKPSTUW]|[A-HK-Y][0-9][ABEHMNPRVWXY])[0-9][ABD-HJLNP-UW-Z]{2})$`),
+	"DEU": regexp.MustCompile(`^[0-9]{5}$`),
+}
+
+// NormalizePostalCode upper-cases the postal code and removes its spaces. For
+// the countries with a known format (USA, CAN, GBR and DEU) the postal code is
+// also validated and ZIP+4 codes are reduced to the 5-digit ZIP.
+//
+// country must already be normalized, see NormalizeCountry.
+func NormalizePostalCode(country, postalcode string) (string, error) {
+	// There's a bug with postal code containing a space
+	// https://github.com/SchedulesDirect/JSON-Service/issues/31
+	p := strings.ToUpper(strings.Replace(strings.TrimSpace(postalcode), " ", "", -1))
+
+	if p == "" {
+		return "", PostalCodeError{country, postalcode}
+	}
+
+	if re, ok := postalCodeValidators[country]; ok && !re.MatchString(p) {
+		return "", PostalCodeError{country, postalcode}
+	}
+
+	if country == "USA" && len(p) > 5 {
+		p = p[:5]
+	}
+
+	return p, nil
+}
+
+var countryAliases = map[string]string{
+	"Arab Republic of Egypt":           "EGY",
+	"Argentine Republic":               "ARG",
+	"Bolivarian Republic of Venezuela": "VEN",
+	"Bolivia":                          "BOL",
+	"Bonaire":                          "BES",
+	"Britain":                          "GBR",
+	"British Virgin Islands":           "VGB",
+	"Brunei":                           "BRN",
+	"Burma":                            "MMR",
+	"Cape Verde":                       "CPV",
+	"Commonwealth of Dominica":         "DMA",
+	"Commonwealth of the Bahamas":      "BHS",
+	"Commonwealth of the Northern Mariana Islands":     "MNP",
+	"Congo-Brazzaville":                                "COG",
+	"Congo-Kinshasa":                                   "COD",
+	"Cote d'Ivoire":                                    "CIV",
+	"Czech Republic":                                   "CZE",
+	"DR Congo":                                         "COD",
+	"Democratic People's Republic of Korea":            "PRK",
+	"Democratic Republic of Sao Tome and Principe":     "STP",
+	"Democratic Republic of Timor-Leste":               "TLS",
+	"Democratic Republic of the Congo":                 "COD",
+	"Democratic Socialist Republic of Sri Lanka":       "LKA",
+	"East Timor":                                       "TLS",
+	"Eastern Republic of Uruguay":                      "URY",
+	"Falkland Islands":                                 "FLK",
+	"Federal Democratic Republic of Ethiopia":          "ETH",
+	"Federal Democratic Republic of Nepal":             "NPL",
+	"Federal Republic of Germany":                      "DEU",
+	"Federal Republic of Nigeria":                      "NGA",
+	"Federal Republic of Somalia":                      "SOM",
+	"Federated States of Micronesia":                   "FSM",
+	"Federative Republic of Brazil":                    "BRA",
+	"French Republic":                                  "FRA",
+	"Gabonese Republic":                                "GAB",
+	"Grand Duchy of Luxembourg":                        "LUX",
+	"Great Britain":                                    "GBR",
+	"Hashemite Kingdom of Jordan":                      "JOR",
+	"Hellenic Republic":                                "GRC",
+	"Holy See":                                         "VAT",
+	"Hong Kong Special Administrative Region of China": "HKG",
+	"Independent State of Papua New Guinea":            "PNG",
+	"Independent State of Samoa":                       "WSM",
+	"Iran":                                             "IRN",
+	"Islamic Republic of Afghanistan":                  "AFG",
+	"Islamic Republic of Iran":                         "IRN",
+	"Islamic Republic of Mauritania":                   "MRT",
+	"Islamic Republic of Pakistan":                     "PAK",
+	"Italian Republic":                                 "ITA",
+	"Ivory Coast":                                      "CIV",
+	"Kingdom of Bahrain":                               "BHR",
+	"Kingdom of Belgium":                               "BEL",
+	"Kingdom of Bhutan":                                "BTN",
+	"Kingdom of Cambodia":                              "KHM",
+	"Kingdom of Denmark":                               "DNK",
+	"Kingdom of Eswatini":                              "SWZ",
+	"Kingdom of Lesotho":                               "LSO",
+	"Kingdom of Morocco":                               "MAR",
+	"Kingdom of Norway":                                "NOR",
+	"Kingdom of Saudi Arabia":                          "SAU",
+	"Kingdom of Spain":                                 "ESP",
+	"Kingdom of Sweden":                                "SWE",
+	"Kingdom of Thailand":                              "THA",
+	"Kingdom of Tonga":                                 "TON",
+	"Kingdom of the Netherlands":                       "NLD",
+	"Kyrgyz Republic":                                  "KGZ",
+	"Laos":                                             "LAO",
+	"Lebanese Republic":                                "LBN",
+	"Macao Special Administrative Region of China":     "MAC",
+	"Macedonia":                                        "MKD",
+	"Micronesia":                                       "FSM",
+	"Moldova":                                          "MDA",
+	"North Korea":                                      "PRK",
+	"Palestine":                                        "PSE",
+	"People's Democratic Republic of Algeria":          "DZA",
+	"People's Republic of Bangladesh":                  "BGD",
+	"People's Republic of China":                       "CHN",
+	"Plurinational State of Bolivia":                   "BOL",
+	"Portuguese Republic":                              "PRT",
+	"Principality of Andorra":                          "AND",
+	"Principality of Liechtenstein":                    "LIE",
+	"Principality of Monaco":                           "MCO",
+	"Republic of Albania":                              "ALB",
+	"Republic of Angola":                               "AGO",
+	"Republic of Armenia":                              "ARM",
+	"Republic of Austria":                              "AUT",
+	"Republic of Azerbaijan":                           "AZE",
+	"Republic of Belarus":                              "BLR",
+	"Republic of Benin":                                "BEN",
+	"Republic of Bosnia and Herzegovina":               "BIH",
+	"Republic of Botswana":                             "BWA",
+	"Republic of Bulgaria":                             "BGR",
+	"Republic of Burundi":                              "BDI",
+	"Republic of Cabo Verde":                           "CPV",
+	"Republic of Cameroon":                             "CMR",
+	"Republic of Chad":                                 "TCD",
+	"Republic of Chile":                                "CHL",
+	"Republic of Colombia":                             "COL",
+	"Republic of Costa Rica":                           "CRI",
+	"Republic of Croatia":                              "HRV",
+	"Republic of Cuba":                                 "CUB",
+	"Republic of Cyprus":                               "CYP",
+	"Republic of Côte d'Ivoire":                        "CIV",
+	"Republic of Djibouti":                             "DJI",
+	"Republic of Ecuador":                              "ECU",
+	"Republic of El Salvador":                          "SLV",
+	"Republic of Equatorial Guinea":                    "GNQ",
+	"Republic of Estonia":                              "EST",
+	"Republic of Fiji":                                 "FJI",
+	"Republic of Finland":                              "FIN",
+	"Republic of Ghana":                                "GHA",
+	"Republic of Guatemala":                            "GTM",
+	"Republic of Guinea":                               "GIN",
+	"Republic of Guinea-Bissau":                        "GNB",
+	"Republic of Guyana":                               "GUY",
+	"Republic of Haiti":                                "HTI",
+	"Republic of Honduras":                             "HND",
+	"Republic of Iceland":                              "ISL",
+	"Republic of India":                                "IND",
+	"Republic of Indonesia":                            "IDN",
+	"Republic of Iraq":                                 "IRQ",
+	"Republic of Ireland":                              "IRL",
+	"Republic of Kazakhstan":                           "KAZ",
+	"Republic of Kenya":                                "KEN",
+	"Republic of Kiribati":                             "KIR",
+	"Republic of Korea":                                "KOR",
+	"Republic of Latvia":                               "LVA",
+	"Republic of Liberia":                              "LBR",
+	"Republic of Lithuania":                            "LTU",
+	"Republic of Madagascar":                           "MDG",
+	"Republic of Malawi":                               "MWI",
+	"Republic of Maldives":                             "MDV",
+	"Republic of Mali":                                 "MLI",
+	"Republic of Malta":                                "MLT",
+	"Republic of Mauritius":                            "MUS",
+	"Republic of Moldova":                              "MDA",
+	"Republic of Mozambique":                           "MOZ",
+	"Republic of Myanmar":                              "MMR",
+	"Republic of Namibia":                              "NAM",
+	"Republic of Nauru":                                "NRU",
+	"Republic of Nicaragua":                            "NIC",
+	"Republic of North Macedonia":                      "MKD",
+	"Republic of Palau":                                "PLW",
+	"Republic of Panama":                               "PAN",
+	"Republic of Paraguay":                             "PRY",
+	"Republic of Peru":                                 "PER",
+	"Republic of Poland":                               "POL",
+	"Republic of San Marino":                           "SMR",
+	"Republic of Senegal":                              "SEN",
+	"Republic of Serbia":                               "SRB",
+	"Republic of Seychelles":                           "SYC",
+	"Republic of Sierra Leone":                         "SLE",
+	"Republic of Singapore":                            "SGP",
+	"Republic of Slovenia":                             "SVN",
+	"Republic of South Africa":                         "ZAF",
+	"Republic of South Sudan":                          "SSD",
+	"Republic of Suriname":                             "SUR",
+	"Republic of Tajikistan":                           "TJK",
+	"Republic of Trinidad and Tobago":                  "TTO",
+	"Republic of Tunisia":                              "TUN",
+	"Republic of Türkiye":                              "TUR",
+	"Republic of Uganda":                               "UGA",
+	"Republic of Uzbekistan":                           "UZB",
+	"Republic of Vanuatu":                              "VUT",
+	"Republic of Yemen":                                "YEM",
+	"Republic of Zambia":                               "ZMB",
+	"Republic of Zimbabwe":                             "ZWE",
+	"Republic of the Congo":                            "COG",
+	"Republic of the Gambia":                           "GMB",
+	"Republic of the Marshall Islands":                 "MHL",
+	"Republic of the Niger":                            "NER",
+	"Republic of the Philippines":                      "PHL",
+	"Republic of the Sudan":                            "SDN",
+	"Russia":                                           "RUS",
+	"Rwandese Republic":                                "RWA",
+	"Saint Helena":                                     "SHN",
+	"Saint Martin":                                     "MAF",
+	"Sint Maarten":                                     "SXM",
+	"Slovak Republic":                                  "SVK",
+	"Socialist Republic of Viet Nam":                   "VNM",
+	"South Korea":                                      "KOR",
+	"State of Israel":                                  "ISR",
+	"State of Kuwait":                                  "KWT",
+	"State of Qatar":                                   "QAT",
+	"Sultanate of Oman":                                "OMN",
+	"Swaziland":                                        "SWZ",
+	"Swiss Confederation":                              "CHE",
+	"Syria":                                            "SYR",
+	"Taiwan":                                           "TWN",
+	"Tanzania":                                         "TZA",
+	"The Bahamas":                                      "BHS",
+	"The Gambia":                                       "GMB",
+	"The Netherlands":                                  "NLD",
+	"Togolese Republic":                                "TGO",
+	"Turkey":                                           "TUR",
+	"U.S. Virgin Islands":                              "VIR",
+	"UAE":                                              "ARE",
+	"UK":                                               "GBR",
+	"US Virgin Islands":                                "VIR",
+	"Union of the Comoros":                             "COM",
+	"United Kingdom of Great Britain and Northern Ireland": "GBR",
+	"United Mexican States":                                "MEX",
+	"United Republic of Tanzania":                          "TZA",
+	"United States of America":                             "USA",
+	"Vatican":                                              "VAT",
+	"Vatican City":                                         "VAT",
+	"Venezuela":                                            "VEN",
+	"Vietnam":                                              "VNM",
+	"Virgin Islands of the United States":                  "VIR",
+	"the State of Eritrea":                                 "ERI",
+	"the State of Palestine":                               "PSE",
+}
+
+var countries = []country{
+	{"AW", "ABW", "Aruba"},
+	{"AF", "AFG", "Afghanistan"},
+	{"AO", "AGO", "Angola"},
+	{"AI", "AIA", "Anguilla"},
+	{"AX", "ALA", "Åland Islands"},
+	{"AL", "ALB", "Albania"},
+	{"AD", "AND", "Andorra"},
+	{"AE", "ARE", "United Arab Emirates"},
+	{"AR", "ARG", "Argentina"},
+	{"AM", "ARM", "Armenia"},
+	{"AS", "ASM", "American Samoa"},
+	{"AQ", "ATA", "Antarctica"},
+	{"TF", "ATF", "French Southern Territories"},
+	{"AG", "ATG", "Antigua and Barbuda"},
+	{"AU", "AUS", "Australia"},
+	{"AT", "AUT", "Austria"},
+	{"AZ", "AZE", "Azerbaijan"},
+	{"BI", "BDI", "Burundi"},
+	{"BE", "BEL", "Belgium"},
+	{"BJ", "BEN", "Benin"},
+	{"BQ", "BES", "Bonaire, Sint Eustatius and Saba"},
+	{"BF", "BFA", "Burkina Faso"},
+	{"BD", "BGD", "Bangladesh"},
+	{"BG", "BGR", "Bulgaria"},
+	{"BH", "BHR", "Bahrain"},
+	{"BS", "BHS", "Bahamas"},
+	{"BA", "BIH", "Bosnia and Herzegovina"},
+	{"BL", "BLM", "Saint Barthélemy"},
+	{"BY", "BLR", "Belarus"},
+	{"BZ", "BLZ", "Belize"},
+	{"BM", "BMU", "Bermuda"},
+	{"BO", "BOL", "Bolivia, Plurinational State of"},
+	{"BR", "BRA", "Brazil"},
+	{"BB", "BRB", "Barbados"},
+	{"BN", "BRN", "Brunei Darussalam"},
+	{"BT", "BTN", "Bhutan"},
+	{"BV", "BVT", "Bouvet Island"},
+	{"BW", "BWA", "Botswana"},
+	{"CF", "CAF", "Central African Republic"},
+	{"CA", "CAN", "Canada"},
+	{"CC", "CCK", "Cocos (Keeling) Islands"},
+	{"CH", "CHE", "Switzerland"},
+	{"CL", "CHL", "Chile"},
+	{"CN", "CHN", "China"},
+	{"CI", "CIV", "Côte d'Ivoire"},
+	{"CM", "CMR", "Cameroon"},
+	{"CD", "COD", "Congo, The Democratic Republic of the"},
+	{"CG", "COG", "Congo"},
+	{"CK", "COK", "Cook Islands"},
+	{"CO", "COL", "Colombia"},
+	{"KM", "COM", "Comoros"},
+	{"CV", "CPV", "Cabo Verde"},
+	{"CR", "CRI", "Costa Rica"},
+	{"CU", "CUB", "Cuba"},
+	{"CW", "CUW", "Curaçao"},
+	{"CX", "CXR", "Christmas Island"},
+	{"KY", "CYM", "Cayman Islands"},
+	{"CY", "CYP", "Cyprus"},
+	{"CZ", "CZE", "Czechia"},
+	{"DE", "DEU", "Germany"},
+	{"DJ", "DJI", "Djibouti"},
+	{"DM", "DMA", "Dominica"},
+	{"DK", "DNK", "Denmark"},
+	{"DO", "DOM", "Dominican Republic"},
+	{"DZ", "DZA", "Algeria"},
+	{"EC", "ECU", "Ecuador"},
+	{"EG", "EGY", "Egypt"},
+	{"ER", "ERI", "Eritrea"},
+	{"EH", "ESH", "Western Sahara"},
+	{"ES", "ESP", "Spain"},
+	{"EE", "EST", "Estonia"},
+	{"ET", "ETH", "Ethiopia"},
+	{"FI", "FIN", "Finland"},
+	{"FJ", "FJI", "Fiji"},
+	{"FK", "FLK", "Falkland Islands (Malvinas)"},
+	{"FR", "FRA", "France"},
+	{"FO", "FRO", "Faroe Islands"},
+	{"FM", "FSM", "Micronesia, Federated States of"},
+	{"GA", "GAB", "Gabon"},
+	{"GB", "GBR", "United Kingdom"},
+	{"GE", "GEO", "Georgia"},
+	{"GG", "GGY", "Guernsey"},
+	{"GH", "GHA", "Ghana"},
+	{"GI", "GIB", "Gibraltar"},
+	{"GN", "GIN", "Guinea"},
+	{"GP", "GLP", "Guadeloupe"},
+	{"GM", "GMB", "Gambia"},
+	{"GW", "GNB", "Guinea-Bissau"},
+	{"GQ", "GNQ", "Equatorial Guinea"},
+	{"GR", "GRC", "Greece"},
+	{"GD", "GRD", "Grenada"},
+	{"GL", "GRL", "Greenland"},
+	{"GT", "GTM", "Guatemala"},
+	{"GF", "GUF", "French Guiana"},
+	{"GU", "GUM", "Guam"},
+	{"GY", "GUY", "Guyana"},
+	{"HK", "HKG", "Hong Kong"},
+	{"HM", "HMD", "Heard Island and McDonald Islands"},
+	{"HN", "HND", "Honduras"},
+	{"HR", "HRV", "Croatia"},
+	{"HT", "HTI", "Haiti"},
+	{"HU", "HUN", "Hungary"},
+	{"ID", "IDN", "Indonesia"},
+	{"IM", "IMN", "Isle of Man"},
+	{"IN", "IND", "India"},
+	{"IO", "IOT", "British Indian Ocean Territory"},
+	{"IE", "IRL", "Ireland"},
+	{"IR", "IRN", "Iran, Islamic Republic of"},
+	{"IQ", "IRQ", "Iraq"},
+	{"IS", "ISL", "Iceland"},
+	{"IL", "ISR", "Israel"},
+	{"IT", "ITA", "Italy"},
+	{"JM", "JAM", "Jamaica"},
+	{"JE", "JEY", "Jersey"},
+	{"JO", "JOR", "Jordan"},
+	{"JP", "JPN", "Japan"},
+	{"KZ", "KAZ", "Kazakhstan"},
+	{"KE", "KEN", "Kenya"},
+	{"KG", "KGZ", "Kyrgyzstan"},
+	{"KH", "KHM", "Cambodia"},
+	{"KI", "KIR", "Kiribati"},
+	{"KN", "KNA", "Saint Kitts and Nevis"},
+	{"KR", "KOR", "Korea, Republic of"},
+	{"KW", "KWT", "Kuwait"},
+	{"LA", "LAO", "Lao People's Democratic Republic"},
+	{"LB", "LBN", "Lebanon"},
+	{"LR", "LBR", "Liberia"},
+	{"LY", "LBY", "Libya"},
+	{"LC", "LCA", "Saint Lucia"},
+	{"LI", "LIE", "Liechtenstein"},
+	{"LK", "LKA", "Sri Lanka"},
+	{"LS", "LSO", "Lesotho"},
+	{"LT", "LTU", "Lithuania"},
+	{"LU", "LUX", "Luxembourg"},
+	{"LV", "LVA", "Latvia"},
+	{"MO", "MAC", "Macao"},
+	{"MF", "MAF", "Saint Martin (French part)"},
+	{"MA", "MAR", "Morocco"},
+	{"MC", "MCO", "Monaco"},
+	{"MD", "MDA", "Moldova, Republic of"},
+	{"MG", "MDG", "Madagascar"},
+	{"MV", "MDV", "Maldives"},
+	{"MX", "MEX", "Mexico"},
+	{"MH", "MHL", "Marshall Islands"},
+	{"MK", "MKD", "North Macedonia"},
+	{"ML", "MLI", "Mali"},
+	{"MT", "MLT", "Malta"},
+	{"MM", "MMR", "Myanmar"},
+	{"ME", "MNE", "Montenegro"},
+	{"MN", "MNG", "Mongolia"},
+	{"MP", "MNP", "Northern Mariana Islands"},
+	{"MZ", "MOZ", "Mozambique"},
+	{"MR", "MRT", "Mauritania"},
+	{"MS", "MSR", "Montserrat"},
+	{"MQ", "MTQ", "Martinique"},
+	{"MU", "MUS", "Mauritius"},
+	{"MW", "MWI", "Malawi"},
+	{"MY", "MYS", "Malaysia"},
+	{"YT", "MYT", "Mayotte"},
+	{"NA", "NAM", "Namibia"},
+	{"NC", "NCL", "New Caledonia"},
+	{"NE", "NER", "Niger"},
+	{"NF", "NFK", "Norfolk Island"},
+	{"NG", "NGA", "Nigeria"},
+	{"NI", "NIC", "Nicaragua"},
+	{"NU", "NIU", "Niue"},
+	{"NL", "NLD", "Netherlands"},
+	{"NO", "NOR", "Norway"},
+	{"NP", "NPL", "Nepal"},
+	{"NR", "NRU", "Nauru"},
+	{"NZ", "NZL", "New Zealand"},
+	{"OM", "OMN", "Oman"},
+	{"PK", "PAK", "Pakistan"},
+	{"PA", "PAN", "Panama"},
+	{"PN", "PCN", "Pitcairn"},
+	{"PE", "PER", "Peru"},
+	{"PH", "PHL", "Philippines"},
+	{"PW", "PLW", "Palau"},
+	{"PG", "PNG", "Papua New Guinea"},
+	{"PL", "POL", "Poland"},
+	{"PR", "PRI", "Puerto Rico"},
+	{"KP", "PRK", "Korea, Democratic People's Republic of"},
+	{"PT", "PRT", "Portugal"},
+	{"PY", "PRY", "Paraguay"},
+	{"PS", "PSE", "Palestine, State of"},
+	{"PF", "PYF", "French Polynesia"},
+	{"QA", "QAT", "Qatar"},
+	{"RE", "REU", "Réunion"},
+	{"RO", "ROU", "Romania"},
+	{"RU", "RUS", "Russian Federation"},
+	{"RW", "RWA", "Rwanda"},
+	{"SA", "SAU", "Saudi Arabia"},
+	{"SD", "SDN", "Sudan"},
+	{"SN", "SEN", "Senegal"},
+	{"SG", "SGP", "Singapore"},
+	{"GS", "SGS", "South Georgia and the South Sandwich Islands"},
+	{"SH", "SHN", "Saint Helena, Ascension and Tristan da Cunha"},
+	{"SJ", "SJM", "Svalbard and Jan Mayen"},
+	{"SB", "SLB", "Solomon Islands"},
+	{"SL", "SLE", "Sierra Leone"},
+	{"SV", "SLV", "El Salvador"},
+	{"SM", "SMR", "San Marino"},
+	{"SO", "SOM", "Somalia"},
+	{"PM", "SPM", "Saint Pierre and Miquelon"},
+	{"RS", "SRB", "Serbia"},
+	{"SS", "SSD", "South Sudan"},
+	{"ST", "STP", "Sao Tome and Principe"},
+	{"SR", "SUR", "Suriname"},
+	{"SK", "SVK", "Slovakia"},
+	{"SI", "SVN", "Slovenia"},
+	{"SE", "SWE", "Sweden"},
+	{"SZ", "SWZ", "Eswatini"},
+	{"SX", "SXM", "Sint Maarten (Dutch part)"},
+	{"SC", "SYC", "Seychelles"},
+	{"SY", "SYR", "Syrian Arab Republic"},
+	{"TC", "TCA", "Turks and Caicos Islands"},
+	{"TD", "TCD", "Chad"},
+	{"TG", "TGO", "Togo"},
+	{"TH", "THA", "Thailand"},
+	{"TJ", "TJK", "Tajikistan"},
+	{"TK", "TKL", "Tokelau"},
+	{"TM", "TKM", "Turkmenistan"},
+	{"TL", "TLS", "Timor-Leste"},
+	{"TO", "TON", "Tonga"},
+	{"TT", "TTO", "Trinidad and Tobago"},
+	{"TN", "TUN", "Tunisia"},
+	{"TR", "TUR", "Türkiye"},
+	{"TV", "TUV", "Tuvalu"},
+	{"TW", "TWN", "Taiwan, Province of China"},
+	{"TZ", "TZA", "Tanzania, United Republic of"},
+	{"UG", "UGA", "Uganda"},
+	{"UA", "UKR", "Ukraine"},
+	{"UM", "UMI", "United States Minor Outlying Islands"},
+	{"UY", "URY", "Uruguay"},
+	{"US", "USA", "United States"},
+	{"UZ", "UZB", "Uzbekistan"},
+	{"VA", "VAT", "Holy See (Vatican City State)"},
+	{"VC", "VCT", "Saint Vincent and the Grenadines"},
+	{"VE", "VEN", "Venezuela, Bolivarian Republic of"},
+	{"VG", "VGB", "Virgin Islands, British"},
+	{"VI", "VIR", "Virgin Islands, U.S."},
+	{"VN", "VNM", "Viet Nam"},
+	{"VU", "VUT", "Vanuatu"},
+	{"WF", "WLF", "Wallis and Futuna"},
+	{"WS", "WSM", "Samoa"},
+	{"YE", "YEM", "Yemen"},
+	{"ZA", "ZAF", "South Africa"},
+	{"ZM", "ZMB", "Zambia"},
+	{"ZW", "ZWE", "Zimbabwe"},
+}
@@ -0,0 +1,88 @@
+package schedulesdirect
+
+import (
+	"testing"
+)
+
+func TestNormalizeCountry(t *testing.T) {
+	tests := map[string]string{
+		"CAN":                      "CAN",
+		"ca":                       "CAN",
+		"USA ":                     "USA",
+		"United States of America": "USA",
+		"germany":                  "DEU",
+		"UK":                       "GBR",
+		"Russia":                   "RUS",
+		"republic of korea":        "KOR",
+		"Ivory Coast":              "CIV",
+		"Turkey":                   "TUR",
+		"Holy See":                 "VAT",
+	}
+
+	for in, expect := range tests {
+		out, err := NormalizeCountry(in)
+		if err != nil {
+			t.Fatalf("%s: %s", in, err)
+		} else if out != expect {
+			t.Fatalf("NormalizeCountry(%q) (%s) != %s", in, out, expect)
+		}
+	}
+}
+
+func TestNormalizeCountryFails(t *testing.T) {
+	_, err := NormalizeCountry("Atlantis")
+	if _, ok := err.(CountryError); !ok {
+		t.Fatalf("err isn't a CountryError: %v", err)
+	}
+}
+
+func TestNormalizePostalCode(t *testing.T) {
+	tests := []struct {
+		country    string
+		postalcode string
+		expect     string
+	}{
+		{"USA", "90210", "90210"},
+		{"USA", "90210-1234", "90210"},
+		{"CAN", "h0h 0h0", "H0H0H0"},
+		{"GBR", "SW1A 1AA", "SW1A1AA"},
+		{"GBR", "M1 1AE", "M11AE"},
+		{"DEU", "10115", "10115"},
+		{"FRA", "75 001", "75001"},
+	}
+
+	for _, test := range tests {
+		out, err := NormalizePostalCode(test.country, test.postalcode)
+		if err != nil {
+			t.Fatal(err)
+		} else if out != test.expect {
+			t.Fatalf("NormalizePostalCode(%s, %q) (%s) != %s", test.country, test.postalcode, out, test.expect)
+		}
+	}
+}
+
+func TestNormalizePostalCodeFails(t *testing.T) {
+	tests := [][2]string{
+		{"USA", "9021"},
+		{"CAN", "D0H 0H0"},
+		{"GBR", "12345"},
+		{"DEU", "1011"},
+		{"FRA", " "},
+	}
+
+	for _, test := range tests {
+		_, err := NormalizePostalCode(test[0], test[1])
+		if _, ok := err.(PostalCodeError); !ok {
+			t.Fatalf("%s %q: err isn't a PostalCodeError: %v", test[0], test[1], err)
+		}
+	}
+}
+
+func TestGetHeadendsFailsInvalidCountry(t *testing.T) {
+	setup()
+
+	_, err := client.GetHeadends("token1", "CNA", "H0H 0H0")
+	if _, ok := err.(CountryError); !ok {
+		t.Fatalf("err isn't a CountryError: %v", err)
+	}
+}
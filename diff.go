@@ -0,0 +1,237 @@
+package schedulesdirect
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+type ChangeKind int
+
+const (
+	AiringAdded ChangeKind = iota
+	AiringRemoved
+	AiringMoved
+	AiringChanged
+)
+
+var changeKindNames = map[ChangeKind]string{
+	AiringAdded:   "Added",
+	AiringRemoved: "Removed",
+	AiringMoved:   "Moved",
+	AiringChanged: "Changed",
+}
+
+func (k ChangeKind) String() string {
+	return changeKindNames[k]
+}
+
+// Change is an airing difference between two snapshots. Old is nil for an
+// added airing and New for a removed one.
+type Change struct {
+	Kind      ChangeKind
+	StationID string
+	ProgramID string
+	Old       *Airing
+	New       *Airing
+}
+
+// Affects tells if the change concerns the airing of a recording.
+func (c Change) Affects(rec Recording) bool {
+	return c.Old != nil && rec.Airing != nil &&
+		c.Old.StationID == rec.StationID &&
+		c.Old.ProgramID == rec.ProgramID &&
+		c.Old.AirDateTime.Equal(rec.AirDateTime)
+}
+
+// DiffSchedules compares two snapshots of schedules and programs. Airings are
+// matched by station and programID, at the same time first; the remaining
+// ones are matched in order as moved airings (a different start or duration).
+// A matched airing is changed when the md5 of its schedule entry or of its
+// program is different.
+//
+// The snapshots are compared as a whole, an airing missing from the new one
+// because it's outside of the downloaded days is reported as removed.
+func DiffSchedules(oldSchedules []schedule, oldPrograms []program, newSchedules []schedule, newPrograms []program) []Change {
+	o := NewGuide(oldSchedules, oldPrograms)
+	n := NewGuide(newSchedules, newPrograms)
+
+	stationIDs := o.StationIDs()
+	for _, id := range n.StationIDs() {
+		if _, ok := o.stations[id]; !ok {
+			stationIDs = append(stationIDs, id)
+		}
+	}
+
+	changes := []Change{}
+
+	for _, stationID := range stationIDs {
+		oldAirings := airingsByProgram(o.Airings(stationID))
+		newAirings := airingsByProgram(n.Airings(stationID))
+
+		for programID, olds := range oldAirings {
+			news := newAirings[programID]
+
+			var oldLeft, newLeft []*Airing
+			matched := make(map[*Airing]bool)
+
+			for _, a := range olds {
+				found := false
+				for _, b := range news {
+					if !matched[b] && a.AirDateTime.Equal(b.AirDateTime) {
+						matched[b] = true
+						found = true
+						changes = append(changes, diffAiring(a, b)...)
+						break
+					}
+				}
+				if !found {
+					oldLeft = append(oldLeft, a)
+				}
+			}
+
+			for _, b := range news {
+				if !matched[b] {
+					newLeft = append(newLeft, b)
+				}
+			}
+
+			for i := 0; i < len(oldLeft) || i < len(newLeft); i++ {
+				switch {
+				case i >= len(newLeft):
+					changes = append(changes, Change{AiringRemoved, stationID, programID, oldLeft[i], nil})
+				case i >= len(oldLeft):
+					changes = append(changes, Change{AiringAdded, stationID, programID, nil, newLeft[i]})
+				default:
+					changes = append(changes, Change{AiringMoved, stationID, programID, oldLeft[i], newLeft[i]})
+				}
+			}
+		}
+
+		for programID, news := range newAirings {
+			if _, ok := oldAirings[programID]; ok {
+				continue
+			}
+			for _, b := range news {
+				changes = append(changes, Change{AiringAdded, stationID, programID, nil, b})
+			}
+		}
+	}
+
+	sort.Sort(changesByStation(changes))
+
+	return changes
+}
+
+func diffAiring(a, b *Airing) []Change {
+	if a.Duration != b.Duration {
+		return []Change{{AiringMoved, a.StationID, a.ProgramID, a, b}}
+	}
+
+	if a.Md5 != b.Md5 || programMd5(a) != programMd5(b) {
+		return []Change{{AiringChanged, a.StationID, a.ProgramID, a, b}}
+	}
+
+	return nil
+}
+
+func programMd5(a *Airing) string {
+	if a.Program == nil {
+		return ""
+	}
+	return a.Program.Md5
+}
+
+func airingsByProgram(airings []*Airing) map[string][]*Airing {
+	m := make(map[string][]*Airing)
+	for _, a := range airings {
+		m[a.ProgramID] = append(m[a.ProgramID], a)
+	}
+	return m
+}
+
+// ChangeNotifier sends changes to subscribers.
+type ChangeNotifier struct {
+	mu          sync.Mutex
+	next        int
+	subscribers map[int]changeSubscriber
+}
+
+type changeSubscriber struct {
+	stationIDs []string
+	handler    func(Change)
+}
+
+func NewChangeNotifier() *ChangeNotifier {
+	return &ChangeNotifier{subscribers: make(map[int]changeSubscriber)}
+}
+
+// Subscribe registers a handler for the changes of some stations, all the
+// stations when stationIDs is empty. The returned id is used to unsubscribe.
+func (cn *ChangeNotifier) Subscribe(stationIDs []string, handler func(Change)) int {
+	cn.mu.Lock()
+	defer cn.mu.Unlock()
+
+	cn.next++
+	cn.subscribers[cn.next] = changeSubscriber{append([]string{}, stationIDs...), handler}
+
+	return cn.next
+}
+
+func (cn *ChangeNotifier) Unsubscribe(id int) {
+	cn.mu.Lock()
+	defer cn.mu.Unlock()
+
+	delete(cn.subscribers, id)
+}
+
+// Publish calls the handlers with the changes, in order and in the order of
+// subscription. A handler can subscribe or unsubscribe, it takes effect at
+// the next Publish.
+func (cn *ChangeNotifier) Publish(changes []Change) {
+	cn.mu.Lock()
+	ids := make([]int, 0, len(cn.subscribers))
+	for id := range cn.subscribers {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	subscribers := make([]changeSubscriber, len(ids))
+	for i, id := range ids {
+		subscribers[i] = cn.subscribers[id]
+	}
+	cn.mu.Unlock()
+
+	for _, c := range changes {
+		for _, s := range subscribers {
+			if len(s.stationIDs) == 0 || containsString(s.stationIDs, c.StationID) {
+				s.handler(c)
+			}
+		}
+	}
+}
+
+// start returns the start of the new airing, of the old one when removed.
+func (c Change) start() time.Time {
+	if c.New != nil {
+		return c.New.AirDateTime
+	}
+	return c.Old.AirDateTime
+}
+
+type changesByStation []Change
+
+func (c changesByStation) Len() int      { return len(c) }
+func (c changesByStation) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+func (c changesByStation) Less(i, j int) bool {
+	if c[i].StationID != c[j].StationID {
+		return c[i].StationID < c[j].StationID
+	}
+	if si, sj := c[i].start(), c[j].start(); !si.Equal(sj) {
+		return si.Before(sj)
+	}
+	if c[i].ProgramID != c[j].ProgramID {
+		return c[i].ProgramID < c[j].ProgramID
+	}
+	return c[i].Kind < c[j].Kind
+}
@@ -0,0 +1,117 @@
+package schedulesdirect
+
+import (
+	"testing"
+)
+
+func TestDiffSchedules(t *testing.T) {
+	oldSchedule, err := JsonToSchedules([]byte(`{"stationID":"10001","programs":[
+{"airDateTime":"2014-07-30T01:00:00Z","duration":1800,"md5":"a","programID":"EP000000010001"},
+{"airDateTime":"2014-07-30T01:30:00Z","duration":1800,"md5":"b","programID":"EP000000010002"},
+{"airDateTime":"2014-07-30T02:00:00Z","duration":1800,"md5":"c","programID":"EP000000010003"},
+{"airDateTime":"2014-07-30T02:30:00Z","duration":1800,"md5":"d","programID":"EP000000010004"},
+{"airDateTime":"2014-07-30T03:00:00Z","duration":1800,"md5":"e","programID":"EP000000010005"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	newSchedule, err := JsonToSchedules([]byte(`{"stationID":"10001","programs":[
+{"airDateTime":"2014-07-30T01:00:00Z","duration":1800,"md5":"a","programID":"EP000000010001"},
+{"airDateTime":"2014-07-30T01:30:00Z","duration":1800,"md5":"B","programID":"EP000000010002"},
+{"airDateTime":"2014-07-30T02:00:00Z","duration":1800,"md5":"f","programID":"EP000000010006"},
+{"airDateTime":"2014-07-30T02:30:00Z","duration":1800,"md5":"d","programID":"EP000000010004"},
+{"airDateTime":"2014-07-30T03:30:00Z","duration":1800,"md5":"e","programID":"EP000000010005"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldPrograms := []program{{ProgramID: "EP000000010004", Md5: "x"}}
+	newPrograms := []program{{ProgramID: "EP000000010004", Md5: "y"}}
+
+	changes := DiffSchedules([]schedule{oldSchedule}, oldPrograms, []schedule{newSchedule}, newPrograms)
+
+	expected := []struct {
+		kind      ChangeKind
+		programID string
+	}{
+		{AiringChanged, "EP000000010002"},
+		{AiringRemoved, "EP000000010003"},
+		{AiringAdded, "EP000000010006"},
+		{AiringChanged, "EP000000010004"},
+		{AiringMoved, "EP000000010005"},
+	}
+
+	if len(changes) != len(expected) {
+		t.Fatalf("len(changes) != %d: %v", len(expected), changes)
+	}
+	for i, e := range expected {
+		if changes[i].Kind != e.kind || changes[i].ProgramID != e.programID || changes[i].StationID != "10001" {
+			t.Fatalf("changes[%d] != %v %s: %v %s", i, e.kind, e.programID, changes[i].Kind, changes[i].ProgramID)
+		}
+	}
+
+	moved := changes[4]
+	if !moved.Old.AirDateTime.Equal(testTime("2014-07-30T03:00:00Z")) || !moved.New.AirDateTime.Equal(testTime("2014-07-30T03:30:00Z")) {
+		t.Fatalf("moved doesn't match: %v %v", moved.Old.AirDateTime, moved.New.AirDateTime)
+	}
+
+	if changes := DiffSchedules([]schedule{oldSchedule}, oldPrograms, []schedule{oldSchedule}, oldPrograms); len(changes) != 0 {
+		t.Fatalf("len(changes) != 0: %v", changes)
+	}
+
+	g := NewGuide([]schedule{oldSchedule}, oldPrograms)
+	rec := Recording{Airing: g.Airings("10001")[4]}
+	if !moved.Affects(rec) || changes[0].Affects(rec) {
+		t.Fatal("Affects doesn't match")
+	}
+}
+
+func TestChangeNotifier(t *testing.T) {
+	cn := NewChangeNotifier()
+
+	var all, station []string
+	idAll := cn.Subscribe(nil, func(c Change) { all = append(all, c.ProgramID) })
+	cn.Subscribe([]string{"10002"}, func(c Change) { station = append(station, c.ProgramID) })
+
+	changes := []Change{
+		{Kind: AiringAdded, StationID: "10001", ProgramID: "EP000000010001"},
+		{Kind: AiringRemoved, StationID: "10002", ProgramID: "EP000000020001"},
+	}
+
+	cn.Publish(changes)
+
+	if len(all) != 2 || all[0] != "EP000000010001" || all[1] != "EP000000020001" {
+		t.Fatalf("all doesn't match: %v", all)
+	}
+	if len(station) != 1 || station[0] != "EP000000020001" {
+		t.Fatalf("station doesn't match: %v", station)
+	}
+
+	cn.Unsubscribe(idAll)
+	cn.Publish(changes)
+
+	if len(all) != 2 {
+		t.Fatalf("len(all) != 2: %v", all)
+	}
+	if len(station) != 2 {
+		t.Fatalf("len(station) != 2: %v", station)
+	}
+}
+
+func TestChangeNotifierResubscribe(t *testing.T) {
+	cn := NewChangeNotifier()
+
+	calls := 0
+	var id int
+	id = cn.Subscribe(nil, func(c Change) {
+		calls++
+		cn.Unsubscribe(id)
+		cn.Subscribe([]string{"10002"}, func(Change) {})
+	})
+
+	cn.Publish([]Change{{Kind: AiringAdded, StationID: "10001", ProgramID: "EP000000010001"}})
+	cn.Publish([]Change{{Kind: AiringAdded, StationID: "10001", ProgramID: "EP000000010001"}})
+
+	if calls != 1 {
+		t.Fatalf("calls != 1: %d", calls)
+	}
+}
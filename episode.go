@@ -0,0 +1,123 @@
+package schedulesdirect
+
+import (
+	"fmt"
+	"sort"
+)
+
+// metadata providers in order of precedence, the others come after sorted by name
+var metadataPrecedence = []string{"Gracenote", "TVmaze", "TheTVDB"}
+
+// EpisodeNumbers are the season, episode and part numbers of a program, each
+// taken from the first provider having it. They are 1-based, 0 is unknown.
+type EpisodeNumbers struct {
+	Season        int
+	Episode       int
+	TotalSeasons  int
+	TotalEpisodes int
+	Part          int
+	TotalParts    int
+
+	// provider of Season, Episode and their totals
+	Provider string
+}
+
+func metadataProviders(p program) []string {
+	seen := make(map[string]bool)
+	var providers []string
+
+	for _, m := range p.Metadata {
+		for name := range m {
+			if !seen[name] {
+				seen[name] = true
+				providers = append(providers, name)
+			}
+		}
+	}
+
+	sort.Sort(byPrecedence(providers))
+
+	return providers
+}
+
+type byPrecedence []string
+
+func (p byPrecedence) rank(i int) int {
+	for r, name := range metadataPrecedence {
+		if name == p[i] {
+			return r
+		}
+	}
+	return len(metadataPrecedence)
+}
+
+func (p byPrecedence) Len() int      { return len(p) }
+func (p byPrecedence) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p byPrecedence) Less(i, j int) bool {
+	if ri, rj := p.rank(i), p.rank(j); ri != rj {
+		return ri < rj
+	}
+	return p[i] < p[j]
+}
+
+func (p program) metadata(provider string) programMetadata {
+	for _, m := range p.Metadata {
+		if v, ok := m[provider]; ok {
+			return v
+		}
+	}
+	return programMetadata{}
+}
+
+// EpisodeNumbers resolves the numbers from the metadata providers, false when
+// no provider gives the season nor the episode.
+func (p program) EpisodeNumbers() (EpisodeNumbers, bool) {
+	var e EpisodeNumbers
+
+	for _, name := range metadataProviders(p) {
+		m := p.metadata(name)
+
+		// the totals of another provider may count differently
+		if e.Provider == "" && (m.Season > 0 || m.Episode > 0) {
+			e.Season = m.Season
+			e.Episode = m.Episode
+			e.TotalSeasons = m.TotalSeasons
+			e.TotalEpisodes = m.TotalEpisodes
+			e.Provider = name
+		}
+		if e.Part == 0 {
+			e.Part = m.Part
+			e.TotalParts = m.TotalParts
+		}
+	}
+
+	return e, e.Provider != ""
+}
+
+// SxxEyy formats the numbers like S03E07, the unknown parts are omitted.
+func (e EpisodeNumbers) SxxEyy() string {
+	s := ""
+	if e.Season > 0 {
+		s += fmt.Sprintf("S%02d", e.Season)
+	}
+	if e.Episode > 0 {
+		s += fmt.Sprintf("E%02d", e.Episode)
+	}
+	return s
+}
+
+// XMLTVNS formats the numbers for the xmltv_ns episode-num system (0-based).
+func (e EpisodeNumbers) XMLTVNS() string {
+	number := func(n, total int) string {
+		s := ""
+		if n > 0 {
+			s = fmt.Sprint(n - 1)
+		}
+		if total > 0 {
+			s += fmt.Sprintf("/%d", total)
+		}
+		return s
+	}
+
+	return number(e.Season, e.TotalSeasons) + " . " + number(e.Episode, e.TotalEpisodes) + " . " + number(e.Part, e.TotalParts)
+}
@@ -0,0 +1,58 @@
+package schedulesdirect
+
+import (
+	"testing"
+)
+
+func TestProgramEpisodeNumbers(t *testing.T) {
+	p, err := JsonToProgram([]byte(`{"programID":"EP012345670007","metadata":[{"TVmaze":{"season":3,"episode":8,"totalEpisodes":22}},{"Gracenote":{"season":3,"episode":7,"totalEpisodes":20,"totalSeasons":5}},{"Other":{"part":1,"totalParts":2}}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e, ok := p.EpisodeNumbers()
+	if !ok {
+		t.Fatal("no episode numbers")
+	}
+
+	if e.Provider != "Gracenote" || e.Season != 3 || e.Episode != 7 {
+		t.Fatalf("e doesn't match: %v", e)
+	}
+	if e.TotalSeasons != 5 || e.TotalEpisodes != 20 || e.Part != 1 || e.TotalParts != 2 {
+		t.Fatalf("e doesn't match: %v", e)
+	}
+
+	if e.SxxEyy() != "S03E07" {
+		t.Fatalf(`e.SxxEyy() != "S03E07": %s`, e.SxxEyy())
+	}
+	if e.XMLTVNS() != "2/5 . 6/20 . 0/2" {
+		t.Fatalf(`e.XMLTVNS() != "2/5 . 6/20 . 0/2": %s`, e.XMLTVNS())
+	}
+
+	// the totals of TVmaze don't go with the numbers of Gracenote
+	p, err = JsonToProgram([]byte(`{"programID":"EP012345670007","metadata":[{"TVmaze":{"season":3,"episode":8,"totalEpisodes":22,"totalSeasons":4}},{"Gracenote":{"season":3,"episode":7}}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e, _ = p.EpisodeNumbers()
+	if e.TotalSeasons != 0 || e.TotalEpisodes != 0 || e.XMLTVNS() != "2 . 6 . " {
+		t.Fatalf("e doesn't match: %v %s", e, e.XMLTVNS())
+	}
+}
+
+func TestProgramEpisodeNumbersMissing(t *testing.T) {
+	p, err := JsonToProgram([]byte(`{"programID":"MV000123450000"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := p.EpisodeNumbers(); ok {
+		t.Fail()
+	}
+
+	e := EpisodeNumbers{Episode: 12}
+	if e.SxxEyy() != "E12" || e.XMLTVNS() != " . 11 . " {
+		t.Fatalf("e doesn't match: %s %s", e.SxxEyy(), e.XMLTVNS())
+	}
+}
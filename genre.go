@@ -0,0 +1,282 @@
+package schedulesdirect
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// GenreCategory is a stable top-level category, the first level of the DVB
+// content nibbles plus Movie.
+type GenreCategory string
+
+const (
+	CategoryMovie     GenreCategory = "Movie"
+	CategoryDrama     GenreCategory = "Drama"
+	CategoryNews      GenreCategory = "News"
+	CategoryShow      GenreCategory = "Show"
+	CategorySports    GenreCategory = "Sports"
+	CategoryKids      GenreCategory = "Kids"
+	CategoryMusic     GenreCategory = "Music"
+	CategoryArts      GenreCategory = "Arts"
+	CategorySocial    GenreCategory = "Social"
+	CategoryEducation GenreCategory = "Education"
+	CategoryLeisure   GenreCategory = "Leisure"
+	CategoryOther     GenreCategory = "Other"
+)
+
+var categoryNibbles = map[byte]GenreCategory{
+	0x1: CategoryDrama,
+	0x2: CategoryNews,
+	0x3: CategoryShow,
+	0x4: CategorySports,
+	0x5: CategoryKids,
+	0x6: CategoryMusic,
+	0x7: CategoryArts,
+	0x8: CategorySocial,
+	0x9: CategoryEducation,
+	0xA: CategoryLeisure,
+}
+
+var categoryXMLTV = map[GenreCategory]string{
+	CategoryMovie:     "Movie / Drama",
+	CategoryDrama:     "Movie / Drama",
+	CategoryNews:      "News / Current affairs",
+	CategoryShow:      "Show / Game show",
+	CategorySports:    "Sports",
+	CategoryKids:      "Children's / Youth programs",
+	CategoryMusic:     "Music / Ballet / Dance",
+	CategoryArts:      "Arts / Culture (without music)",
+	CategorySocial:    "Social / Political issues / Economics",
+	CategoryEducation: "Education / Science / Factual topics",
+	CategoryLeisure:   "Leisure hobbies",
+}
+
+// XMLTV returns the DVB category name used by XMLTV, empty for CategoryOther.
+func (c GenreCategory) XMLTV() string {
+	return categoryXMLTV[c]
+}
+
+// lower-cased Schedules Direct genres to DVB content nibbles
+var genreNibbles = map[string]byte{
+	"action":            0x12,
+	"adults only":       0x18,
+	"adventure":         0x12,
+	"agriculture":       0x91,
+	"animals":           0x91,
+	"animated":          0x55,
+	"anime":             0x55,
+	"anthology":         0x10,
+	"art":               0x70,
+	"auto":              0xA3,
+	"auto racing":       0x47,
+	"awards":            0x32,
+	"baseball":          0x45,
+	"basketball":        0x45,
+	"biography":         0x83,
+	"boxing":            0x4B,
+	"bus./financial":    0x82,
+	"business":          0x82,
+	"children":          0x50,
+	"children's music":  0x50,
+	"collectibles":      0xA2,
+	"comedy":            0x14,
+	"comedy drama":      0x14,
+	"community":         0x80,
+	"consumer":          0x82,
+	"consumer advocacy": 0x82,
+	"cooking":           0xA5,
+	"cricket":           0x45,
+	"crime":             0x11,
+	"crime drama":       0x11,
+	"current affairs":   0x20,
+	"dance":             0x66,
+	"docudrama":         0x17,
+	"documentary":       0x23,
+	"drama":             0x10,
+	"educational":       0x90,
+	"entertainment":     0x30,
+	"environment":       0x91,
+	"event":             0x41,
+	"exercise":          0xA4,
+	"fantasy":           0x13,
+	"fashion":           0x7B,
+	"figure skating":    0x49,
+	"fishing":           0xA0,
+	"fitness":           0xA4,
+	"football":          0x45,
+	"game show":         0x31,
+	"golf":              0x40,
+	"health":            0xA4,
+	"historical drama":  0x17,
+	"history":           0x90,
+	"hockey":            0x45,
+	"home improvement":  0xA2,
+	"horror":            0x13,
+	"horse":             0x4A,
+	"house/garden":      0xA7,
+	"how-to":            0xA2,
+	"interview":         0x24,
+	"law":               0x80,
+	"martial arts":      0x4B,
+	"medical":           0x93,
+	"military":          0x81,
+	"motorsports":       0x47,
+	"music":             0x60,
+	"music talk":        0x60,
+	"musical":           0x65,
+	"mystery":           0x11,
+	"nature":            0x91,
+	"news":              0x20,
+	"newsmagazine":      0x22,
+	"olympics":          0x41,
+	"outdoors":          0xA0,
+	"paranormal":        0x13,
+	"parenting":         0x82,
+	"politics":          0x80,
+	"pro wrestling":     0x4B,
+	"public affairs":    0x80,
+	"reality":           0x30,
+	"religious":         0x73,
+	"romance":           0x16,
+	"romantic comedy":   0x16,
+	"rugby":             0x45,
+	"science":           0x92,
+	"science fiction":   0x13,
+	"self improvement":  0xA4,
+	"shopping":          0xA6,
+	"sitcom":            0x14,
+	"skiing":            0x49,
+	"soap":              0x15,
+	"soccer":            0x43,
+	"special":           0x30,
+	"sports event":      0x40,
+	"sports non-event":  0x42,
+	"sports talk":       0x42,
+	"suspense":          0x11,
+	"swimming":          0x48,
+	"talk":              0x33,
+	"technology":        0x92,
+	"tennis":            0x44,
+	"thriller":          0x11,
+	"track/field":       0x46,
+	"travel":            0xA1,
+	"variety":           0x32,
+	"volleyball":        0x45,
+	"war":               0x12,
+	"weather":           0x21,
+	"western":           0x12,
+	"wrestling":         0x4B,
+}
+
+// Genre is a Schedules Direct genre with its category and DVB content nibbles
+// (0xF0, user defined, when unknown).
+type Genre struct {
+	Name     string
+	Category GenreCategory
+	Nibble   byte
+}
+
+func LookupGenre(name string) Genre {
+	nibble, ok := genreNibbles[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return Genre{name, CategoryOther, 0xF0}
+	}
+
+	return Genre{name, categoryNibbles[nibble>>4], nibble}
+}
+
+// GenreInfo returns the Genres of the program with their category.
+func (p program) GenreInfo() []Genre {
+	genres := make([]Genre, 0, len(p.Genres))
+	for _, name := range p.Genres {
+		genres = append(genres, LookupGenre(name))
+	}
+	return genres
+}
+
+// Categories returns the sorted unique categories of the program, movies are
+// recognized by their programID.
+func (p program) Categories() []GenreCategory {
+	seen := make(map[GenreCategory]bool)
+	categories := []GenreCategory{}
+
+	add := func(c GenreCategory) {
+		if !seen[c] {
+			seen[c] = true
+			categories = append(categories, c)
+		}
+	}
+
+	if id, err := ParseProgramID(p.ProgramID); err == nil {
+		switch id.Kind {
+		case ProgramMovie:
+			add(CategoryMovie)
+		case ProgramSports:
+			add(CategorySports)
+		}
+	}
+
+	for _, g := range p.GenreInfo() {
+		add(g.Category)
+	}
+
+	sort.Sort(genreCategories(categories))
+
+	return categories
+}
+
+func (p program) HasCategory(categories ...GenreCategory) bool {
+	for _, c := range p.Categories() {
+		for _, want := range categories {
+			if c == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FilterPrograms returns the programs in one of the categories.
+func FilterPrograms(programs []program, categories ...GenreCategory) []program {
+	result := []program{}
+	for _, p := range programs {
+		if p.HasCategory(categories...) {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// FilterAirings returns the airings in one of the categories, the airings
+// without program details are ignored.
+func FilterAirings(airings []*Airing, categories ...GenreCategory) []*Airing {
+	result := []*Airing{}
+	for _, a := range airings {
+		if a.Program != nil && a.Program.HasCategory(categories...) {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+// AiringsInCategories returns the airings of the stations overlapping
+// [from, to) in one of the categories, sorted by start. For example the
+// sports events of a lineup in the next 24 hours.
+func (g *Guide) AiringsInCategories(stationIDs []string, from, to time.Time, categories ...GenreCategory) []*Airing {
+	var airings []*Airing
+	for _, id := range stationIDs {
+		airings = append(airings, FilterAirings(g.Between(id, from, to), categories...)...)
+	}
+
+	result := append([]*Airing{}, airings...)
+	sort.Stable(airingsByStart(result))
+
+	return result
+}
+
+type genreCategories []GenreCategory
+
+func (g genreCategories) Len() int           { return len(g) }
+func (g genreCategories) Swap(i, j int)      { g[i], g[j] = g[j], g[i] }
+func (g genreCategories) Less(i, j int) bool { return g[i] < g[j] }
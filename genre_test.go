@@ -0,0 +1,64 @@
+package schedulesdirect
+
+import (
+	"testing"
+)
+
+func TestLookupGenre(t *testing.T) {
+	tests := map[string]Genre{
+		"Soccer":          {"Soccer", CategorySports, 0x43},
+		"science fiction": {"science fiction", CategoryDrama, 0x13},
+		"Children":        {"Children", CategoryKids, 0x50},
+		"genre1":          {"genre1", CategoryOther, 0xF0},
+	}
+
+	for in, expect := range tests {
+		if g := LookupGenre(in); g != expect {
+			t.Fatalf("LookupGenre(%q) (%v) != %v", in, g, expect)
+		}
+	}
+
+	if CategoryKids.XMLTV() != "Children's / Youth programs" {
+		t.Fatalf("CategoryKids.XMLTV() doesn't match: %s", CategoryKids.XMLTV())
+	}
+}
+
+func TestProgramCategories(t *testing.T) {
+	p := program{ProgramID: "MV000123450000", Genres: []string{"Action", "Comedy", "genre1"}}
+
+	categories := p.Categories()
+	if len(categories) != 3 || categories[0] != CategoryDrama || categories[1] != CategoryMovie || categories[2] != CategoryOther {
+		t.Fatalf("categories don't match: %v", categories)
+	}
+
+	programs := FilterPrograms([]program{p, {ProgramID: "SP000123450001"}, {Genres: []string{"News"}}}, CategorySports, CategoryNews)
+	if len(programs) != 2 {
+		t.Fatalf("len(programs) != 2: %d", len(programs))
+	}
+}
+
+func TestGuideAiringsInCategories(t *testing.T) {
+	s1, err := JsonToSchedules([]byte(`{"stationID":"10001","programs":[{"airDateTime":"2014-07-30T01:00:00Z","duration":3600,"programID":"SP000000010001"},{"airDateTime":"2014-07-30T02:00:00Z","duration":3600,"programID":"EP000000020001"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2, err := JsonToSchedules([]byte(`{"stationID":"10002","programs":[{"airDateTime":"2014-07-30T00:00:00Z","duration":3600,"programID":"EP000000030001"},{"airDateTime":"2014-07-31T02:00:00Z","duration":3600,"programID":"EP000000030002"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewGuide([]schedule{s1, s2}, []program{
+		{ProgramID: "SP000000010001", Genres: []string{"Hockey"}},
+		{ProgramID: "EP000000020001", Genres: []string{"Sitcom"}},
+		{ProgramID: "EP000000030001", Genres: []string{"Sports talk"}},
+		{ProgramID: "EP000000030002", Genres: []string{"Sports talk"}},
+	})
+
+	airings := g.AiringsInCategories([]string{"10001", "10002"}, testTime("2014-07-30T00:00:00Z"), testTime("2014-07-31T00:00:00Z"), CategorySports)
+	if len(airings) != 2 {
+		t.Fatalf("len(airings) != 2: %d", len(airings))
+	}
+	if airings[0].ProgramID != "EP000000030001" || airings[1].ProgramID != "SP000000010001" {
+		t.Fatalf("airings don't match: %s %s", airings[0].ProgramID, airings[1].ProgramID)
+	}
+}
@@ -0,0 +1,180 @@
+package schedulesdirect
+
+import (
+	"errors"
+	"time"
+)
+
+const DefaultGridSlot = 30 * time.Minute
+
+// GridOptions is the window of a grid: Slots columns of SlotDuration
+// (DefaultGridSlot when 0) from Start. Times are converted to Location (UTC
+// when nil).
+type GridOptions struct {
+	Start        time.Time
+	Slots        int
+	SlotDuration time.Duration
+	Location     *time.Location
+}
+
+type Grid struct {
+	Start        time.Time   `json:"start"`
+	End          time.Time   `json:"end"`
+	SlotDuration int         `json:"slotDuration"` // seconds
+	Slots        []time.Time `json:"slots"`
+	Rows         []GridRow   `json:"rows"`
+}
+
+type GridRow struct {
+	Channel   string     `json:"channel"`
+	StationID string     `json:"stationID"`
+	Callsign  string     `json:"callsign"`
+	Name      string     `json:"name"`
+	Cells     []GridCell `json:"cells"`
+}
+
+// GridCell spans Span slots from the Column slot. Start and End are clipped to
+// the window (and to the previous airing when the data overlaps),
+// ClippedStart and ClippedEnd tell if the airing goes beyond them. A NoData
+// cell fills a gap without airing.
+type GridCell struct {
+	Column       int       `json:"column"`
+	Span         int       `json:"span"`
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	ProgramID    string    `json:"programID,omitempty"`
+	Title        string    `json:"title,omitempty"`
+	New          bool      `json:"new,omitempty"`
+	Live         bool      `json:"live,omitempty"`
+	HD           bool      `json:"hd,omitempty"`
+	ClippedStart bool      `json:"clippedStart,omitempty"`
+	ClippedEnd   bool      `json:"clippedEnd,omitempty"`
+	NoData       bool      `json:"noData,omitempty"`
+}
+
+// BuildGrid builds a row for each channel of the mapping, sorted by channel.
+func BuildGrid(cm channelMapping, schedules []schedule, programs []program, opts GridOptions) (Grid, error) {
+	if opts.Slots <= 0 {
+		return Grid{}, errors.New("opts.Slots <= 0")
+	}
+
+	slot := opts.SlotDuration
+	if slot == 0 {
+		slot = DefaultGridSlot
+	} else if slot < 0 {
+		return Grid{}, errors.New("opts.SlotDuration < 0")
+	}
+
+	loc := opts.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	start := opts.Start.In(loc)
+	end := start.Add(time.Duration(opts.Slots) * slot)
+
+	grid := Grid{
+		Start:        start,
+		End:          end,
+		SlotDuration: int(slot / time.Second),
+		Slots:        make([]time.Time, opts.Slots),
+		Rows:         []GridRow{},
+	}
+
+	for i := range grid.Slots {
+		grid.Slots[i] = start.Add(time.Duration(i) * slot)
+	}
+
+	column := func(t time.Time) int {
+		return int(t.Sub(start) / slot)
+	}
+
+	// first slot after the cell, a cell always spans at least one slot
+	span := func(from, to time.Time) int {
+		last := int((to.Sub(start) + slot - 1) / slot)
+		if n := last - column(from); n > 0 {
+			return n
+		}
+		return 1
+	}
+
+	guide := NewGuide(schedules, programs)
+	lineup := NewLineup(cm)
+
+	cm.Map = append([]channelMapEntry{}, cm.Map...)
+	cm.SortMap()
+
+	for _, m := range cm.Map {
+		row := GridRow{
+			Channel:   m.Channel,
+			StationID: m.StationId,
+			Cells:     []GridCell{},
+		}
+
+		if s, ok := lineup.Station(m.StationId); ok {
+			row.Callsign = s.Callsign
+			row.Name = s.Name
+		}
+
+		noData := func(from, to time.Time) {
+			row.Cells = append(row.Cells, GridCell{
+				Column: column(from),
+				Span:   span(from, to),
+				Start:  from,
+				End:    to,
+				NoData: true,
+			})
+		}
+
+		cursor := start
+
+		for _, a := range guide.Between(m.StationId, start, end) {
+			from, to := a.Start().In(loc), a.End().In(loc)
+
+			// skip overlapping airings
+			if !to.After(cursor) {
+				continue
+			}
+
+			if cursor.Before(from) {
+				noData(cursor, from)
+			}
+
+			cell := GridCell{
+				ProgramID:    a.ProgramID,
+				New:          a.New,
+				Live:         a.IsLive(),
+				HD:           a.IsHD(),
+				ClippedStart: from.Before(cursor),
+				ClippedEnd:   to.After(end),
+			}
+
+			if a.Program != nil {
+				cell.Title = a.Program.Titles["title120"]
+			}
+
+			if cell.ClippedStart {
+				from = cursor
+			}
+			if cell.ClippedEnd {
+				to = end
+			}
+
+			cell.Column = column(from)
+			cell.Span = span(from, to)
+			cell.Start = from
+			cell.End = to
+
+			row.Cells = append(row.Cells, cell)
+			cursor = to
+		}
+
+		if cursor.Before(end) {
+			noData(cursor, end)
+		}
+
+		grid.Rows = append(grid.Rows, row)
+	}
+
+	return grid, nil
+}
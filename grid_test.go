@@ -0,0 +1,82 @@
+package schedulesdirect
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestBuildGrid(t *testing.T) {
+	var cm channelMapping
+	cm.Map = []channelMapEntry{{"12", "10001"}, {"2", "10002"}}
+	cm.Stations = []station{{Callsign: "callsign1", StationID: "10001"}}
+
+	s, err := JsonToSchedules([]byte(`{"stationID":"10001","programs":[{"airDateTime":"2014-07-30T00:00:00Z","duration":3600,"programID":"program1","new":true},{"airDateTime":"2014-07-30T01:00:00Z","duration":1800,"programID":"program2"},{"airDateTime":"2014-07-30T02:00:00Z","duration":3600,"programID":"program3"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := JsonToProgram([]byte(`{"programID":"program1","titles":{"title120":"title1"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loc := time.FixedZone("EDT", -4*60*60)
+
+	grid, err := BuildGrid(cm, []schedule{s}, []program{p}, GridOptions{
+		Start:    testTime("2014-07-30T00:30:00Z"),
+		Slots:    4,
+		Location: loc,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(grid.Slots) != 4 || grid.SlotDuration != 1800 {
+		t.Fatalf("slots don't match: %v %d", grid.Slots, grid.SlotDuration)
+	}
+	if grid.Start.Location() != loc || grid.Start.Hour() != 20 {
+		t.Fatalf("grid.Start isn't in loc: %s", grid.Start)
+	}
+
+	if len(grid.Rows) != 2 || grid.Rows[0].Channel != "2" {
+		t.Fatalf("rows don't match: %v", grid.Rows)
+	}
+
+	// nothing for station 10002
+	if len(grid.Rows[0].Cells) != 1 || !grid.Rows[0].Cells[0].NoData || grid.Rows[0].Cells[0].Span != 4 {
+		t.Fatalf("grid.Rows[0].Cells don't match: %v", grid.Rows[0].Cells)
+	}
+
+	cells := grid.Rows[1].Cells
+	if grid.Rows[1].Callsign != "callsign1" {
+		t.Fatalf(`grid.Rows[1].Callsign != "callsign1": %s`, grid.Rows[1].Callsign)
+	}
+	if len(cells) != 4 {
+		t.Fatalf("len(cells) != 4: %v", cells)
+	}
+
+	if cells[0].Title != "title1" || !cells[0].ClippedStart || !cells[0].New || cells[0].Column != 0 || cells[0].Span != 1 {
+		t.Fatalf("cells[0] doesn't match: %v", cells[0])
+	}
+	if cells[1].ProgramID != "program2" || cells[1].Column != 1 || cells[1].Span != 1 {
+		t.Fatalf("cells[1] doesn't match: %v", cells[1])
+	}
+	if !cells[2].NoData || cells[2].Column != 2 || cells[2].Span != 1 {
+		t.Fatalf("cells[2] doesn't match: %v", cells[2])
+	}
+	if cells[3].ProgramID != "program3" || !cells[3].ClippedEnd || !cells[3].End.Equal(grid.End) {
+		t.Fatalf("cells[3] doesn't match: %v", cells[3])
+	}
+
+	if _, err := json.Marshal(grid); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildGridFailsNoSlots(t *testing.T) {
+	_, err := BuildGrid(channelMapping{}, []schedule{}, []program{}, GridOptions{})
+	if err == nil {
+		t.Fail()
+	}
+}
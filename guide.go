@@ -0,0 +1,252 @@
+package schedulesdirect
+
+import (
+	"sort"
+	"time"
+)
+
+// Airing is a program of a station's schedule. Program is nil when its
+// details weren't given to the guide.
+type Airing struct {
+	StationID string
+	scheduleProgram
+	Program *program
+}
+
+func (a *Airing) Start() time.Time {
+	return a.AirDateTime
+}
+
+func (a *Airing) End() time.Time {
+	return a.AirDateTime.Add(time.Duration(a.Duration) * time.Second)
+}
+
+// On tells if the airing is on at t, the end is excluded.
+func (a *Airing) On(t time.Time) bool {
+	return !t.Before(a.Start()) && t.Before(a.End())
+}
+
+func (a *Airing) overlaps(from, to time.Time) bool {
+	return a.Start().Before(to) && a.End().After(from)
+}
+
+const (
+	GuideGap = iota
+	GuideOverlap
+)
+
+// GuideProblem is a gap or an overlap between two consecutive airings of a station.
+type GuideProblem struct {
+	Kind      int
+	StationID string
+	Start     time.Time
+	End       time.Time
+	Before    *Airing
+	After     *Airing
+}
+
+// Guide is an in-memory index of schedules and programs.
+type Guide struct {
+	// airings sorted by start
+	stations map[string][]*Airing
+	programs map[string]*program
+
+	// longest airing of each station, bounds the lookups with overlapping data
+	longest map[string]time.Duration
+}
+
+func NewGuide(schedules []schedule, programs []program) *Guide {
+	g := &Guide{
+		stations: make(map[string][]*Airing, len(schedules)),
+		programs: make(map[string]*program, len(programs)),
+		longest:  make(map[string]time.Duration, len(schedules)),
+	}
+
+	for i := range programs {
+		g.programs[programs[i].ProgramID] = &programs[i]
+	}
+
+	for _, s := range schedules {
+		for _, p := range s.Programs {
+			g.stations[s.StationID] = append(g.stations[s.StationID], &Airing{
+				StationID:       s.StationID,
+				scheduleProgram: p,
+				Program:         g.programs[p.ProgramID],
+			})
+		}
+	}
+
+	for id, airings := range g.stations {
+		sort.Sort(airingsByStart(airings))
+
+		// the same airing can be in several schedules
+		deduped := airings[:0]
+		for i, a := range airings {
+			if i > 0 && a.AirDateTime.Equal(deduped[len(deduped)-1].AirDateTime) && a.ProgramID == deduped[len(deduped)-1].ProgramID {
+				continue
+			}
+			deduped = append(deduped, a)
+
+			if d := a.End().Sub(a.Start()); d > g.longest[id] {
+				g.longest[id] = d
+			}
+		}
+		g.stations[id] = deduped
+	}
+
+	return g
+}
+
+// StationIDs returns the sorted stations of the guide.
+func (g *Guide) StationIDs() []string {
+	ids := make([]string, 0, len(g.stations))
+	for id := range g.stations {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
+
+func (g *Guide) Program(programID string) (*program, bool) {
+	p, ok := g.programs[programID]
+	return p, ok
+}
+
+// Airings returns all the airings of a station sorted by start.
+func (g *Guide) Airings(stationID string) []*Airing {
+	return g.stations[stationID]
+}
+
+// At returns the airing on at t.
+func (g *Guide) At(stationID string, t time.Time) (*Airing, bool) {
+	airings := g.stations[stationID]
+
+	// last airing starting at or before t
+	i := sort.Search(len(airings), func(i int) bool {
+		return airings[i].Start().After(t)
+	}) - 1
+
+	// with overlapping data a previous airing can still be on
+	limit := t.Add(-g.longest[stationID])
+	for ; i >= 0 && airings[i].Start().After(limit); i-- {
+		if airings[i].On(t) {
+			return airings[i], true
+		}
+	}
+
+	return nil, false
+}
+
+func (g *Guide) Now(stationID string) (*Airing, bool) {
+	return g.At(stationID, time.Now())
+}
+
+// Next returns the first airing starting after t.
+func (g *Guide) Next(stationID string, t time.Time) (*Airing, bool) {
+	airings := g.stations[stationID]
+
+	i := sort.Search(len(airings), func(i int) bool {
+		return airings[i].Start().After(t)
+	})
+
+	if i == len(airings) {
+		return nil, false
+	}
+
+	return airings[i], true
+}
+
+// Between returns the airings overlapping [from, to).
+func (g *Guide) Between(stationID string, from, to time.Time) []*Airing {
+	airings := g.stations[stationID]
+
+	i := sort.Search(len(airings), func(i int) bool {
+		return !airings[i].Start().Before(from)
+	})
+
+	// airings started before from can still be on
+	limit := from.Add(-g.longest[stationID])
+	for i > 0 && airings[i-1].Start().After(limit) {
+		i--
+	}
+
+	result := []*Airing{}
+	for ; i < len(airings) && airings[i].Start().Before(to); i++ {
+		if airings[i].overlaps(from, to) {
+			result = append(result, airings[i])
+		}
+	}
+
+	return result
+}
+
+// AtAll calls At for each station, for example the StationIDs of a lineup.
+func (g *Guide) AtAll(stationIDs []string, t time.Time) map[string]*Airing {
+	result := make(map[string]*Airing, len(stationIDs))
+	for _, id := range stationIDs {
+		if a, ok := g.At(id, t); ok {
+			result[id] = a
+		}
+	}
+	return result
+}
+
+func (g *Guide) NowAll(stationIDs []string) map[string]*Airing {
+	return g.AtAll(stationIDs, time.Now())
+}
+
+func (g *Guide) NextAll(stationIDs []string, t time.Time) map[string]*Airing {
+	result := make(map[string]*Airing, len(stationIDs))
+	for _, id := range stationIDs {
+		if a, ok := g.Next(id, t); ok {
+			result[id] = a
+		}
+	}
+	return result
+}
+
+func (g *Guide) BetweenAll(stationIDs []string, from, to time.Time) map[string][]*Airing {
+	result := make(map[string][]*Airing, len(stationIDs))
+	for _, id := range stationIDs {
+		if a := g.Between(id, from, to); len(a) > 0 {
+			result[id] = a
+		}
+	}
+	return result
+}
+
+// Problems returns the gaps and overlaps between consecutive airings, sorted
+// by station and start.
+func (g *Guide) Problems() []GuideProblem {
+	problems := []GuideProblem{}
+
+	for _, id := range g.StationIDs() {
+		airings := g.stations[id]
+
+		for i := 1; i < len(airings); i++ {
+			before, after := airings[i-1], airings[i]
+
+			switch {
+			case before.End().Before(after.Start()):
+				problems = append(problems, GuideProblem{GuideGap, id, before.End(), after.Start(), before, after})
+			case before.End().After(after.Start()):
+				problems = append(problems, GuideProblem{GuideOverlap, id, after.Start(), before.End(), before, after})
+			}
+		}
+	}
+
+	return problems
+}
+
+type airingsByStart []*Airing
+
+func (a airingsByStart) Len() int      { return len(a) }
+func (a airingsByStart) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a airingsByStart) Less(i, j int) bool {
+	if !a[i].AirDateTime.Equal(a[j].AirDateTime) {
+		return a[i].AirDateTime.Before(a[j].AirDateTime)
+	}
+	return a[i].ProgramID < a[j].ProgramID
+}
@@ -0,0 +1,101 @@
+package schedulesdirect
+
+import (
+	"testing"
+	"time"
+)
+
+func testGuide(t *testing.T) *Guide {
+	s, err := JsonToSchedules([]byte(`{"stationID":"10001","metadata":{"endDate":"2014-08-12","startDate":"2014-07-30"},"programs":[{"airDateTime":"2014-07-30T01:00:00Z","duration":1800,"programID":"program2"},{"airDateTime":"2014-07-30T00:00:00Z","duration":3600,"programID":"program1"},{"airDateTime":"2014-07-30T02:00:00Z","duration":3600,"programID":"program3"},{"airDateTime":"2014-07-30T02:30:00Z","duration":1800,"programID":"program4"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := JsonToProgram([]byte(`{"programID":"program1","titles":{"title120":"title1"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewGuide([]schedule{s, s}, []program{p})
+}
+
+func testTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestGuideAt(t *testing.T) {
+	g := testGuide(t)
+
+	if len(g.Airings("10001")) != 4 {
+		t.Fatalf("len(g.Airings(\"10001\")) != 4: %d", len(g.Airings("10001")))
+	}
+
+	a, ok := g.At("10001", testTime("2014-07-30T00:59:59Z"))
+	if !ok || a.ProgramID != "program1" {
+		t.Fatalf("airing at 00:59:59 doesn't match: %v", a)
+	}
+	if a.Program == nil || a.Program.Titles["title120"] != "title1" {
+		t.Fatal("program details are missing")
+	}
+
+	a, ok = g.At("10001", testTime("2014-07-30T01:00:00Z"))
+	if !ok || a.ProgramID != "program2" {
+		t.Fatalf("airing at 01:00:00 doesn't match: %v", a)
+	}
+
+	if _, ok := g.At("10001", testTime("2014-07-30T01:45:00Z")); ok {
+		t.Fatal("nothing should be on at 01:45:00")
+	}
+
+	a, ok = g.At("10001", testTime("2014-07-30T02:45:00Z"))
+	if !ok || a.ProgramID != "program4" {
+		t.Fatalf("airing at 02:45:00 doesn't match: %v", a)
+	}
+
+	a, ok = g.Next("10001", testTime("2014-07-30T00:30:00Z"))
+	if !ok || a.ProgramID != "program2" {
+		t.Fatalf("next airing doesn't match: %v", a)
+	}
+
+	if _, ok := g.Next("10001", testTime("2014-07-30T03:00:00Z")); ok {
+		t.Fatal("there shouldn't be a next airing")
+	}
+}
+
+func TestGuideBetween(t *testing.T) {
+	g := testGuide(t)
+
+	airings := g.Between("10001", testTime("2014-07-30T00:30:00Z"), testTime("2014-07-30T01:00:00Z"))
+	if len(airings) != 1 || airings[0].ProgramID != "program1" {
+		t.Fatalf("airings don't match: %v", airings)
+	}
+
+	airings = g.Between("10001", testTime("2014-07-30T02:40:00Z"), testTime("2014-07-30T04:00:00Z"))
+	if len(airings) != 2 {
+		t.Fatalf("len(airings) != 2: %d", len(airings))
+	}
+
+	all := g.BetweenAll([]string{"10001", "10002"}, testTime("2014-07-30T00:00:00Z"), testTime("2014-07-30T01:00:00Z"))
+	if len(all) != 1 || len(all["10001"]) != 1 {
+		t.Fatalf("all doesn't match: %v", all)
+	}
+}
+
+func TestGuideProblems(t *testing.T) {
+	g := testGuide(t)
+
+	problems := g.Problems()
+	if len(problems) != 2 {
+		t.Fatalf("len(problems) != 2: %d", len(problems))
+	}
+	if problems[0].Kind != GuideGap || !problems[0].Start.Equal(testTime("2014-07-30T01:30:00Z")) {
+		t.Fatalf("problems[0] isn't the gap: %v", problems[0])
+	}
+	if problems[1].Kind != GuideOverlap || !problems[1].End.Equal(testTime("2014-07-30T03:00:00Z")) {
+		t.Fatalf("problems[1] isn't the overlap: %v", problems[1])
+	}
+}
@@ -0,0 +1,230 @@
+package schedulesdirect
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const defaultSearchConcurrency = 4
+
+// metroPostalCodes is a sample of postal codes of major metro areas, used when
+// HeadendSearch.PostalCodes is empty: a US city per region and the capital or
+// largest city of each Canadian province and territory.
+var metroPostalCodes = map[string][]string{
+	"USA": {"02108", "10001", "20001", "30303", "40202", "50309", "60601", "70112", "80202", "90012"},
+	"CAN": {"A1C5M2", "B3H4R2", "C1A7N8", "E3B1B4", "G1R4P5", "H2Y1C6", "K1A0A6", "M5V3L9", "P3E2C6", "R3C4T3", "S4P3V7", "T5J2R7", "V6B4Y8", "X1A2L9", "Y1A1B2"},
+}
+
+// HeadendSearch describes a headend lookup over several postal codes.
+//
+// When PostalCodes is empty a sample of major metro areas is searched, only
+// for USA and CAN. It finds the headends of these cities, not the regional
+// ones of smaller towns which need their own PostalCodes.
+//
+// Types (Cable, Antenna, Satellite...) and LineupName (a case insensitive
+// substring) are optional filters.
+type HeadendSearch struct {
+	Country     string
+	PostalCodes []string
+	Types       []string
+	LineupName  string
+	Concurrency int
+}
+
+type HeadendResult struct {
+	ID string
+	headend
+	PostalCodes []string
+}
+
+func (s HeadendSearch) postalCodes() ([]string, error) {
+	if len(s.PostalCodes) > 0 {
+		return s.PostalCodes, nil
+	}
+
+	codes, ok := metroPostalCodes[s.Country]
+	if !ok {
+		return []string{}, fmt.Errorf("no metro postal codes for country %s, PostalCodes are required", s.Country)
+	}
+
+	return codes, nil
+}
+
+func (s HeadendSearch) match(h headend) (headend, bool) {
+	if len(s.Types) > 0 {
+		found := false
+		for _, t := range s.Types {
+			if strings.EqualFold(t, h.Type) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return headend{}, false
+		}
+	}
+
+	if s.LineupName == "" {
+		return h, true
+	}
+
+	name := strings.ToLower(s.LineupName)
+
+	var l []lineup
+	for _, v := range h.Lineups {
+		if strings.Contains(strings.ToLower(v.Name), name) {
+			l = append(l, v)
+		}
+	}
+
+	if len(l) == 0 {
+		return headend{}, false
+	}
+
+	h.Lineups = l
+	return h, true
+}
+
+// HeadendSearchError is the error of the postal codes whose lookup failed.
+type HeadendSearchError map[string]error
+
+func (e HeadendSearchError) Error() string {
+	codes := make([]string, 0, len(e))
+	for code := range e {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	messages := make([]string, len(codes))
+	for i, code := range codes {
+		messages[i] = fmt.Sprintf("%s: %s", code, e[code])
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// SearchHeadends calls GetHeadends for every postal code of the search, merges
+// the headends by ID and returns them sorted by type, location and ID. When
+// some lookups fail, the headends of the others are returned with a
+// HeadendSearchError.
+func (c sdclient) SearchHeadends(token string, s HeadendSearch) ([]HeadendResult, error) {
+	country, err := NormalizeCountry(s.Country)
+	if err != nil {
+		return []HeadendResult{}, err
+	}
+	s.Country = country
+
+	codes, err := s.postalCodes()
+	if err != nil {
+		return []HeadendResult{}, err
+	}
+
+	concurrency := s.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultSearchConcurrency
+	}
+
+	type lookup struct {
+		postalcode string
+		headends   map[string]headend
+		err        error
+	}
+
+	jobs := make(chan string)
+	results := make(chan lookup)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for postalcode := range jobs {
+				h, err := c.GetHeadends(token, s.Country, postalcode)
+				results <- lookup{postalcode, h, err}
+			}
+		}()
+	}
+
+	go func() {
+		for _, postalcode := range codes {
+			jobs <- postalcode
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	merged := make(map[string]*HeadendResult)
+	errs := make(HeadendSearchError)
+
+	for r := range results {
+		if r.err != nil {
+			errs[r.postalcode] = r.err
+			continue
+		}
+
+		for id, h := range r.headends {
+			h, ok := s.match(h)
+			if !ok {
+				continue
+			}
+
+			if m, ok := merged[id]; ok {
+				m.PostalCodes = append(m.PostalCodes, r.postalcode)
+				m.Lineups = mergeLineups(m.Lineups, h.Lineups)
+			} else {
+				merged[id] = &HeadendResult{
+					ID:          id,
+					headend:     h,
+					PostalCodes: []string{r.postalcode},
+				}
+			}
+		}
+	}
+
+	result := make([]HeadendResult, 0, len(merged))
+	for _, m := range merged {
+		sort.Strings(m.PostalCodes)
+		result = append(result, *m)
+	}
+
+	sort.Sort(headendResults(result))
+
+	if len(errs) > 0 {
+		return result, errs
+	}
+
+	return result, nil
+}
+
+func mergeLineups(a, b []lineup) []lineup {
+	seen := make(map[string]bool, len(a))
+	for _, l := range a {
+		seen[l.Uri] = true
+	}
+
+	for _, l := range b {
+		if !seen[l.Uri] {
+			seen[l.Uri] = true
+			a = append(a, l)
+		}
+	}
+
+	return a
+}
+
+type headendResults []HeadendResult
+
+func (h headendResults) Len() int      { return len(h) }
+func (h headendResults) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h headendResults) Less(i, j int) bool {
+	if h[i].Type != h[j].Type {
+		return h[i].Type < h[j].Type
+	}
+	if h[i].Location != h[j].Location {
+		return h[i].Location < h[j].Location
+	}
+	return h[i].ID < h[j].ID
+}
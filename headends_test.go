@@ -0,0 +1,136 @@
+package schedulesdirect
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestSearchHeadendsOK(t *testing.T) {
+	setup()
+
+	mux.HandleFunc(apiVersion+"/headends",
+		func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "GET")
+			testHeader(t, r, "token", "token1")
+			testUrlParameter(t, r, "country", "CAN")
+
+			switch r.URL.Query().Get("postalcode") {
+			case "H0H0H0":
+				fmt.Fprint(w, `{"0000001":{"lineups":[{"name":"Cable1","uri":"uri1"}],"location":"City1","type":"Cable"},"0000002":{"lineups":[{"name":"Antenna1","uri":"uri2"}],"location":"City1","type":"Antenna"}}`)
+			case "H1H1H1":
+				fmt.Fprint(w, `{"0000001":{"lineups":[{"name":"Cable1","uri":"uri1"},{"name":"Cable2","uri":"uri3"}],"location":"City1","type":"Cable"},"0000003":{"lineups":[{"name":"Sat1","uri":"uri4"}],"location":"City2","type":"Satellite"}}`)
+			default:
+				t.Fatalf("unexpected postalcode: %s", r.URL.Query().Get("postalcode"))
+			}
+		},
+	)
+
+	headends, err := client.SearchHeadends("token1", HeadendSearch{
+		Country:     "CAN",
+		PostalCodes: []string{"H0H 0H0", "H1H 1H1"},
+		Types:       []string{"cable", "satellite"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(headends) != 2 {
+		t.Fatalf("len(headends) != 2: %d", len(headends))
+	}
+	if headends[0].ID != "0000001" {
+		t.Fatalf(`headends[0].ID != "0000001": %s`, headends[0].ID)
+	}
+	if len(headends[0].Lineups) != 2 {
+		t.Fatalf("len(headends[0].Lineups) != 2: %d", len(headends[0].Lineups))
+	}
+	if len(headends[0].PostalCodes) != 2 {
+		t.Fatalf("len(headends[0].PostalCodes) != 2: %d", len(headends[0].PostalCodes))
+	}
+	if headends[1].ID != "0000003" {
+		t.Fatalf(`headends[1].ID != "0000003": %s`, headends[1].ID)
+	}
+}
+
+func TestSearchHeadendsLineupName(t *testing.T) {
+	setup()
+
+	mux.HandleFunc(apiVersion+"/headends",
+		func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"0000001":{"lineups":[{"name":"Cable1","uri":"uri1"},{"name":"Digital","uri":"uri2"}],"location":"City1","type":"Cable"},"0000002":{"lineups":[{"name":"Antenna1","uri":"uri3"}],"location":"City1","type":"Antenna"}}`)
+		},
+	)
+
+	headends, err := client.SearchHeadends("token1", HeadendSearch{
+		Country:     "CAN",
+		PostalCodes: []string{"H0H0H0"},
+		LineupName:  "digi",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(headends) != 1 {
+		t.Fatalf("len(headends) != 1: %d", len(headends))
+	} else if len(headends[0].Lineups) != 1 || headends[0].Lineups[0].Uri != "uri2" {
+		t.Fatalf("lineups don't match: %v", headends[0].Lineups)
+	}
+}
+
+func TestSearchHeadendsFailsWithMessage(t *testing.T) {
+	setup()
+
+	mux.HandleFunc(apiVersion+"/headends",
+		func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"response":"INVALID_PARAMETER:POSTALCODE","code":2050,"serverID":"serverID1","message":"Invalid postal code.","datetime":"2014-07-29T23:16:52Z"}`)
+		},
+	)
+
+	_, err := client.SearchHeadends("token1", HeadendSearch{
+		Country:     "CAN",
+		PostalCodes: []string{"H0H0H0"},
+	})
+	if err == nil {
+		t.Fatal("err == nil")
+	} else if err.Error() != "H0H0H0: Invalid postal code." {
+		t.Fatal(err)
+	}
+}
+
+func TestSearchHeadendsFailsNoMetroPostalCodes(t *testing.T) {
+	_, err := NewClient().SearchHeadends("token1", HeadendSearch{Country: "ZZZ"})
+	if err == nil {
+		t.Fail()
+	}
+}
+
+func TestSearchHeadendsPartialFailure(t *testing.T) {
+	setup()
+
+	mux.HandleFunc(apiVersion+"/headends",
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("postalcode") == "H0H0H0" {
+				fmt.Fprint(w, `{"response":"INVALID_PARAMETER:POSTALCODE","code":2050,"serverID":"serverID1","message":"Invalid postal code.","datetime":"2014-07-29T23:16:52Z"}`)
+				return
+			}
+			fmt.Fprint(w, `{"0000001":{"lineups":[{"name":"Cable1","uri":"uri1"}],"location":"City1","type":"Cable"}}`)
+		},
+	)
+
+	headends, err := client.SearchHeadends("token1", HeadendSearch{
+		Country:     "CAN",
+		PostalCodes: []string{"H0H0H0", "H1H1H1"},
+	})
+
+	errSearch, ok := err.(HeadendSearchError)
+	if !ok {
+		t.Fatalf("err isn't a HeadendSearchError: %v", err)
+	}
+	if len(errSearch) != 1 || errSearch["H0H0H0"] == nil {
+		t.Fatalf("errSearch doesn't match: %v", errSearch)
+	}
+
+	if len(headends) != 1 || headends[0].ID != "0000001" {
+		t.Fatalf("headends doesn't match: %v", headends)
+	}
+}
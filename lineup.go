@@ -0,0 +1,96 @@
+package schedulesdirect
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// LineupStation is a station of a channel mapping with all its channels.
+type LineupStation struct {
+	station
+	Channels []string
+}
+
+// Lineup is an indexed view of a channelMapping, joining Map and Stations.
+type Lineup struct {
+	Lineup    string
+	Transport string
+	Modified  time.Time
+
+	// sorted by their first channel
+	Stations []*LineupStation
+
+	byStationID map[string]*LineupStation
+	byCallsign  map[string]*LineupStation
+}
+
+func NewLineup(cm channelMapping) *Lineup {
+	l := &Lineup{
+		Lineup:      cm.Metadata.Lineup,
+		Transport:   cm.Metadata.Transport,
+		Modified:    cm.Metadata.Modified,
+		byStationID: make(map[string]*LineupStation, len(cm.Stations)),
+		byCallsign:  make(map[string]*LineupStation, len(cm.Stations)),
+	}
+
+	for _, s := range cm.Stations {
+		ls := &LineupStation{station: s, Channels: []string{}}
+		l.Stations = append(l.Stations, ls)
+		l.byStationID[s.StationID] = ls
+		if s.Callsign != "" {
+			l.byCallsign[strings.ToUpper(s.Callsign)] = ls
+		}
+	}
+
+	for _, m := range cm.Map {
+		ls, ok := l.byStationID[m.StationId]
+		if !ok {
+			// mapped but without details
+			ls = &LineupStation{Channels: []string{}}
+			ls.StationID = m.StationId
+			l.Stations = append(l.Stations, ls)
+			l.byStationID[m.StationId] = ls
+		}
+		ls.Channels = append(ls.Channels, m.Channel)
+	}
+
+	for _, ls := range l.Stations {
+		sort.Sort(channelStrings(ls.Channels))
+	}
+
+	sort.Sort(lineupStations(l.Stations))
+
+	return l
+}
+
+func (l *Lineup) Station(stationID string) (*LineupStation, bool) {
+	ls, ok := l.byStationID[stationID]
+	return ls, ok
+}
+
+// StationByCallsign is case insensitive.
+func (l *Lineup) StationByCallsign(callsign string) (*LineupStation, bool) {
+	ls, ok := l.byCallsign[strings.ToUpper(callsign)]
+	return ls, ok
+}
+
+type lineupStations []*LineupStation
+
+func (s lineupStations) Len() int      { return len(s) }
+func (s lineupStations) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s lineupStations) Less(i, j int) bool {
+	// stations without channels go last
+	switch {
+	case len(s[i].Channels) == 0 && len(s[j].Channels) == 0:
+		return s[i].StationID < s[j].StationID
+	case len(s[i].Channels) == 0:
+		return false
+	case len(s[j].Channels) == 0:
+		return true
+	case s[i].Channels[0] != s[j].Channels[0]:
+		return lessChannel(s[i].Channels[0], s[j].Channels[0])
+	default:
+		return s[i].StationID < s[j].StationID
+	}
+}
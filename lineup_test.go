@@ -0,0 +1,47 @@
+package schedulesdirect
+
+import (
+	"testing"
+)
+
+func TestNewLineup(t *testing.T) {
+	cm, err := JsonToChannelMapping([]byte(`{"map": [{"channel": "101","stationID": "10001"},{"channel": "12","stationID": "10002"},{"channel": "1933","stationID": "10001"},{"channel": "5","stationID": "10003"}],"metadata": {"lineup": "CAN-0000000-X","modified": "2014-07-29T16:38:09Z","transport": "Cable"},"stations": [{"affiliate": "affiliate1","broadcaster": {"city": "City1","country": "Canada","postalcode": "00000"},"callsign": "callsign1","language": "en","name": "name1","stationID": "10001"},{"callsign": "callsign2","language": "en","logo": {"URL": "https://domain/path/file.png","dimension": "w=360px|h=270px","md5": "ba5b5b5085baac6da247564039c03c9e"},"name": "name2","stationID": "10002"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := NewLineup(cm)
+
+	if l.Lineup != "CAN-0000000-X" || l.Transport != "Cable" || l.Modified.IsZero() {
+		t.Fatalf("metadata doesn't match: %s %s %s", l.Lineup, l.Transport, l.Modified)
+	}
+
+	if len(l.Stations) != 3 {
+		t.Fatalf("len(l.Stations) != 3: %d", len(l.Stations))
+	}
+	if l.Stations[0].StationID != "10003" || l.Stations[1].StationID != "10002" {
+		t.Fatalf("stations aren't sorted by channel: %s %s", l.Stations[0].StationID, l.Stations[1].StationID)
+	}
+
+	s, ok := l.Station("10001")
+	if !ok {
+		t.Fatal("station 10001 not found")
+	}
+	if len(s.Channels) != 2 || s.Channels[0] != "101" || s.Channels[1] != "1933" {
+		t.Fatalf("channels don't match: %v", s.Channels)
+	}
+	if s.Affiliate != "affiliate1" || s.Broadcaster.City != "City1" {
+		t.Fatalf("station details don't match: %s %s", s.Affiliate, s.Broadcaster.City)
+	}
+
+	s, ok = l.StationByCallsign("CALLSIGN2")
+	if !ok {
+		t.Fatal("callsign2 not found")
+	} else if s.Logo.Md5 != "ba5b5b5085baac6da247564039c03c9e" {
+		t.Fatalf("s.Logo.Md5 doesn't match: %s", s.Logo.Md5)
+	}
+
+	if _, ok := l.Station("99999"); ok {
+		t.Fatal("station 99999 shouldn't be found")
+	}
+}
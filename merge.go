@@ -0,0 +1,103 @@
+package schedulesdirect
+
+import (
+	"sort"
+)
+
+// StationChannel is where a station can be found: a channel of a lineup.
+type StationChannel struct {
+	Lineup    string
+	Transport string
+	Channel   string
+}
+
+// StationIndex merges several channel mappings, for example the cable and
+// antenna lineups of a household, so each station is fetched only once.
+type StationIndex struct {
+	stations map[string][]StationChannel
+}
+
+func MergeChannelMappings(cms ...channelMapping) *StationIndex {
+	si := &StationIndex{
+		stations: make(map[string][]StationChannel),
+	}
+
+	for _, cm := range cms {
+		for _, m := range cm.Map {
+			sc := StationChannel{
+				Lineup:    cm.Metadata.Lineup,
+				Transport: cm.Metadata.Transport,
+				Channel:   m.Channel,
+			}
+
+			found := false
+			for _, v := range si.stations[m.StationId] {
+				if v == sc {
+					found = true
+					break
+				}
+			}
+			if !found {
+				si.stations[m.StationId] = append(si.stations[m.StationId], sc)
+			}
+		}
+
+		// stations without channel are still part of the lineup
+		for _, s := range cm.Stations {
+			if _, ok := si.stations[s.StationID]; !ok {
+				si.stations[s.StationID] = []StationChannel{}
+			}
+		}
+	}
+
+	return si
+}
+
+// StationIDs returns the sorted unique stationIDs, ready for GetSchedules.
+func (si *StationIndex) StationIDs() []string {
+	ids := make([]string, 0, len(si.stations))
+	for id := range si.stations {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
+
+// Channels returns the lineups and channels of a station sorted by lineup and channel.
+func (si *StationIndex) Channels(stationID string) []StationChannel {
+	sc := append([]StationChannel{}, si.stations[stationID]...)
+
+	sort.Sort(stationChannels(sc))
+
+	return sc
+}
+
+// Lineups returns the sorted lineups carrying a station.
+func (si *StationIndex) Lineups(stationID string) []string {
+	seen := make(map[string]bool)
+	lineups := []string{}
+
+	for _, sc := range si.stations[stationID] {
+		if !seen[sc.Lineup] {
+			seen[sc.Lineup] = true
+			lineups = append(lineups, sc.Lineup)
+		}
+	}
+
+	sort.Strings(lineups)
+
+	return lineups
+}
+
+type stationChannels []StationChannel
+
+func (s stationChannels) Len() int      { return len(s) }
+func (s stationChannels) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s stationChannels) Less(i, j int) bool {
+	if s[i].Lineup != s[j].Lineup {
+		return s[i].Lineup < s[j].Lineup
+	}
+	return lessChannel(s[i].Channel, s[j].Channel)
+}
@@ -0,0 +1,48 @@
+package schedulesdirect
+
+import (
+	"testing"
+)
+
+func TestMergeChannelMappings(t *testing.T) {
+	var cable, antenna channelMapping
+
+	cable.Metadata.Lineup = "CAN-0000001-X"
+	cable.Metadata.Transport = "Cable"
+	cable.Map = []channelMapEntry{{"12", "10001"}, {"112", "10001"}, {"20", "10002"}}
+
+	antenna.Metadata.Lineup = "CAN-OTA-H0H0H0"
+	antenna.Metadata.Transport = "Antenna"
+	antenna.Map = []channelMapEntry{{"6.1", "10001"}, {"9.1", "10003"}}
+	antenna.Stations = []station{{StationID: "10004"}}
+
+	si := MergeChannelMappings(cable, antenna, cable)
+
+	ids := si.StationIDs()
+	expect := []string{"10001", "10002", "10003", "10004"}
+	if len(ids) != len(expect) {
+		t.Fatalf("len(ids) != %d: %v", len(expect), ids)
+	}
+	for i := range expect {
+		if ids[i] != expect[i] {
+			t.Fatalf("ids[%d] (%s) != %s", i, ids[i], expect[i])
+		}
+	}
+
+	channels := si.Channels("10001")
+	if len(channels) != 3 {
+		t.Fatalf("len(channels) != 3: %v", channels)
+	}
+	if channels[0].Channel != "12" || channels[1].Channel != "112" || channels[2].Channel != "6.1" {
+		t.Fatalf("channels aren't sorted: %v", channels)
+	}
+
+	lineups := si.Lineups("10001")
+	if len(lineups) != 2 {
+		t.Fatalf("len(lineups) != 2: %v", lineups)
+	}
+
+	if len(si.Channels("10004")) != 0 {
+		t.Fatal("station 10004 shouldn't have channels")
+	}
+}
@@ -0,0 +1,243 @@
+package schedulesdirect
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const defaultMirrorConcurrency = 4
+
+// ImageDownload is an image to mirror as Name in the mirror directory. Md5
+// is optional, when set the file is skipped if it already matches.
+type ImageDownload struct {
+	URL  string
+	Md5  string
+	Name string
+}
+
+func imageName(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return ""
+	}
+	return path.Base(u.Path)
+}
+
+// StationLogoDownloads returns the logos of the stations of a channel mapping.
+func StationLogoDownloads(cm channelMapping) []ImageDownload {
+	downloads := []ImageDownload{}
+	for _, s := range cm.Stations {
+		if s.Logo.URL != "" {
+			downloads = append(downloads, ImageDownload{s.Logo.URL, s.Logo.Md5, imageName(s.Logo.URL)})
+		}
+	}
+	return downloads
+}
+
+func (c sdclient) ProgramImageDownloads(programs []program) []ImageDownload {
+	downloads := []ImageDownload{}
+	for _, p := range programs {
+		for _, i := range p.Images {
+			if i.Uri != "" {
+				downloads = append(downloads, ImageDownload{c.ImageURL(i.Uri), i.Md5, imageName(i.Uri)})
+			}
+		}
+	}
+	return downloads
+}
+
+func (c sdclient) ArtworkDownloads(artwork map[string][]artworkImage) []ImageDownload {
+	downloads := []ImageDownload{}
+	for _, images := range artwork {
+		for _, i := range images {
+			downloads = append(downloads, ImageDownload{c.ImageURL(i.Uri), "", imageName(i.Uri)})
+		}
+	}
+	return downloads
+}
+
+// MirrorResult lists the names of the downloaded and skipped images and the
+// errors by name. Names gives the name of each URL, which differs from the
+// ImageDownload's one when another URL already used it.
+type MirrorResult struct {
+	Downloaded []string
+	Skipped    []string
+	Errors     map[string]error
+	Names      map[string]string
+}
+
+// uniqueName adds a hash of the URL to a name: "image.jpg" becomes
+// "image-1a2b3c4d.jpg".
+func uniqueName(name, url string) string {
+	h := md5.Sum([]byte(url))
+	ext := filepath.Ext(name)
+	return strings.TrimSuffix(name, ext) + "-" + hex.EncodeToString(h[:4]) + ext
+}
+
+func fileMd5(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := md5.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// MirrorImages downloads the images to dir with at most concurrency downloads
+// at once (4 when <= 0). Each file is written to a temporary file and renamed
+// so a partial image is never served. The error is non-nil when a download
+// failed, see MirrorResult.Errors.
+func (c sdclient) MirrorImages(token, dir string, downloads []ImageDownload, concurrency int) (MirrorResult, error) {
+	result := MirrorResult{
+		Downloaded: []string{},
+		Skipped:    []string{},
+		Errors:     make(map[string]error),
+		Names:      make(map[string]string),
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return result, err
+	}
+
+	if concurrency <= 0 {
+		concurrency = defaultMirrorConcurrency
+	}
+
+	// the same image can be used by several stations or programs, different
+	// images can have the same file name
+	seen := make(map[string]bool)
+	taken := make(map[string]bool)
+	var unique []ImageDownload
+	for _, d := range downloads {
+		if d.Name == "" || d.Name == "." || d.Name == ".." || strings.ContainsAny(d.Name, `/\`) {
+			result.Errors[d.Name] = fmt.Errorf("invalid image name: %q", d.Name)
+			continue
+		}
+		if seen[d.URL] {
+			continue
+		}
+		seen[d.URL] = true
+
+		if taken[d.Name] {
+			d.Name = uniqueName(d.Name, d.URL)
+		}
+		taken[d.Name] = true
+
+		result.Names[d.URL] = d.Name
+		unique = append(unique, d)
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	for _, d := range unique {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(d ImageDownload) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			skipped, err := c.mirrorImage(token, filepath.Join(dir, d.Name), d)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			switch {
+			case err != nil:
+				result.Errors[d.Name] = err
+			case skipped:
+				result.Skipped = append(result.Skipped, d.Name)
+			default:
+				result.Downloaded = append(result.Downloaded, d.Name)
+			}
+		}(d)
+	}
+
+	wg.Wait()
+
+	sort.Strings(result.Downloaded)
+	sort.Strings(result.Skipped)
+
+	if len(result.Errors) > 0 {
+		return result, fmt.Errorf("%d image(s) failed to download", len(result.Errors))
+	}
+
+	return result, nil
+}
+
+// mirrorImage only sends the token to the service, not to the hosts of the
+// station logos.
+func (c sdclient) mirrorImage(token, name string, d ImageDownload) (bool, error) {
+	if d.Md5 != "" {
+		if sum, err := fileMd5(name); err == nil && strings.EqualFold(sum, d.Md5) {
+			return true, nil
+		}
+	}
+
+	var clientHttp http.Client
+
+	req, errNewRequest := http.NewRequest("GET", d.URL, nil)
+	if errNewRequest != nil {
+		return false, errNewRequest
+	}
+
+	req.Header.Add("User-Agent", "go-schedulesdirect")
+	if token != "" && strings.HasPrefix(d.URL, c.baseURL+"/") {
+		req.Header.Add("token", token)
+	}
+
+	resp, errDo := clientHttp.Do(req)
+	if errDo != nil {
+		return false, errDo
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return false, fmt.Errorf("resp.StatusCode != 200: %d", resp.StatusCode)
+	}
+
+	tmp, errTemp := ioutil.TempFile(filepath.Dir(name), ".download-")
+	if errTemp != nil {
+		return false, errTemp
+	}
+	defer os.Remove(tmp.Name())
+
+	h := md5.New()
+
+	_, errCopy := io.Copy(io.MultiWriter(tmp, h), resp.Body)
+	errClose := tmp.Close()
+	if errCopy != nil {
+		return false, errCopy
+	} else if errClose != nil {
+		return false, errClose
+	}
+
+	if sum := hex.EncodeToString(h.Sum(nil)); d.Md5 != "" && !strings.EqualFold(sum, d.Md5) {
+		return false, fmt.Errorf("md5 doesn't match: %s != %s", sum, d.Md5)
+	}
+
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return false, err
+	}
+
+	return false, os.Rename(tmp.Name(), name)
+}
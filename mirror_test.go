@@ -0,0 +1,140 @@
+package schedulesdirect
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMirrorImages(t *testing.T) {
+	setup()
+
+	var requests int32
+
+	mux.HandleFunc(apiVersion+"/image/",
+		func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			testHeader(t, r, "token", "token1")
+
+			if r.URL.Path == apiVersion+"/image/missing.jpg" {
+				http.NotFound(w, r)
+				return
+			}
+			fmt.Fprint(w, r.URL.Path)
+		},
+	)
+
+	dir, err := ioutil.TempDir("", "mirror")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sum := md5.Sum([]byte(apiVersion + "/image/logo1.png"))
+
+	// already mirrored
+	if err := ioutil.WriteFile(filepath.Join(dir, "logo1.png"), []byte(apiVersion+"/image/logo1.png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var cm channelMapping
+	cm.Stations = make([]station, 2)
+	cm.Stations[0].Logo.URL = client.ImageURL("logo1.png")
+	cm.Stations[0].Logo.Md5 = hex.EncodeToString(sum[:])
+	cm.Stations[1].Logo.URL = client.ImageURL("logo1.png")
+	cm.Stations[1].Logo.Md5 = hex.EncodeToString(sum[:])
+
+	downloads := append(StationLogoDownloads(cm), client.ProgramImageDownloads([]program{
+		{Images: []programImage{{Uri: "assets/image1.jpg"}, {Uri: "missing.jpg"}}},
+	})...)
+
+	result, err := client.MirrorImages("token1", dir, downloads, 2)
+	if err == nil {
+		t.Fatal("err == nil")
+	}
+
+	if len(result.Skipped) != 1 || result.Skipped[0] != "logo1.png" {
+		t.Fatalf("result.Skipped doesn't match: %v", result.Skipped)
+	}
+	if len(result.Downloaded) != 1 || result.Downloaded[0] != "image1.jpg" {
+		t.Fatalf("result.Downloaded doesn't match: %v", result.Downloaded)
+	}
+	if _, ok := result.Errors["missing.jpg"]; !ok || len(result.Errors) != 1 {
+		t.Fatalf("result.Errors doesn't match: %v", result.Errors)
+	}
+	if requests != 2 {
+		t.Fatalf("requests != 2: %d", requests)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "image1.jpg"))
+	if err != nil {
+		t.Fatal(err)
+	} else if string(data) != apiVersion+"/image/assets/image1.jpg" {
+		t.Fatalf("data doesn't match: %s", data)
+	}
+
+	files, _ := ioutil.ReadDir(dir)
+	if len(files) != 2 {
+		t.Fatalf("temporary files weren't removed: %d files", len(files))
+	}
+}
+
+func TestMirrorImagesOtherHost(t *testing.T) {
+	setup()
+
+	mux.HandleFunc(apiVersion+"/image/",
+		func(w http.ResponseWriter, r *http.Request) {
+			testHeader(t, r, "token", "token1")
+			fmt.Fprint(w, r.URL.Path)
+		},
+	)
+
+	cdn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token := r.Header.Get("token"); token != "" {
+			t.Fatalf("token sent to another host: %s", token)
+		}
+		fmt.Fprint(w, r.URL.Path)
+	}))
+	defer cdn.Close()
+
+	dir, err := ioutil.TempDir("", "mirror")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	downloads := []ImageDownload{
+		{URL: cdn.URL + "/a/logo.png", Name: "logo.png"},
+		{URL: cdn.URL + "/b/logo.png", Name: "logo.png"},
+		{URL: cdn.URL + "/a/logo.png", Name: "logo.png"},
+		{URL: client.ImageURL("assets/image1.jpg"), Name: "image1.jpg"},
+	}
+
+	result, err := client.MirrorImages("token1", dir, downloads, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Downloaded) != 3 {
+		t.Fatalf("result.Downloaded doesn't match: %v", result.Downloaded)
+	}
+
+	a, b := result.Names[cdn.URL+"/a/logo.png"], result.Names[cdn.URL+"/b/logo.png"]
+	if a != "logo.png" || b == "logo.png" || filepath.Ext(b) != ".png" {
+		t.Fatalf("names don't match: %s %s", a, b)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, b))
+	if err != nil {
+		t.Fatal(err)
+	} else if string(data) != "/b/logo.png" {
+		t.Fatalf("data doesn't match: %s", data)
+	}
+}
@@ -0,0 +1,119 @@
+package schedulesdirect
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MovieRating is a parsed quality rating, Score is the rating normalized
+// between 0 and 1.
+type MovieRating struct {
+	RatingsBody string
+	Rating      float64
+	Min         float64
+	Max         float64
+	Increment   float64
+	Score       float64
+}
+
+func parseRatingValue(body, field, value string) (float64, error) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	// ParseFloat accepts "NaN" and "Inf" which would pass the range checks
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("%s: invalid %s: %q", body, field, value)
+	}
+	return f, nil
+}
+
+// Parse converts the strings of a quality rating. An empty MinRating is 0 and
+// an empty Increment means any value.
+func (qr qualityRating) Parse() (MovieRating, error) {
+	mr := MovieRating{RatingsBody: qr.RatingsBody}
+
+	var err error
+
+	if mr.Rating, err = parseRatingValue(qr.RatingsBody, "rating", qr.Rating); err != nil {
+		return MovieRating{}, err
+	}
+	if mr.Max, err = parseRatingValue(qr.RatingsBody, "maxRating", qr.MaxRating); err != nil {
+		return MovieRating{}, err
+	}
+	if qr.MinRating != "" {
+		if mr.Min, err = parseRatingValue(qr.RatingsBody, "minRating", qr.MinRating); err != nil {
+			return MovieRating{}, err
+		}
+	}
+	if qr.Increment != "" {
+		if mr.Increment, err = parseRatingValue(qr.RatingsBody, "increment", qr.Increment); err != nil {
+			return MovieRating{}, err
+		}
+	}
+
+	switch {
+	case mr.Max <= mr.Min:
+		return MovieRating{}, fmt.Errorf("%s: maxRating (%s) <= minRating (%s)", qr.RatingsBody, qr.MaxRating, qr.MinRating)
+	case mr.Rating < mr.Min || mr.Rating > mr.Max:
+		return MovieRating{}, fmt.Errorf("%s: rating (%s) out of range", qr.RatingsBody, qr.Rating)
+	case mr.Increment < 0:
+		return MovieRating{}, fmt.Errorf("%s: increment (%s) < 0", qr.RatingsBody, qr.Increment)
+	}
+
+	mr.Score = (mr.Rating - mr.Min) / (mr.Max - mr.Min)
+
+	return mr, nil
+}
+
+// Stars returns the score on a scale of max stars rounded to the half star.
+func (mr MovieRating) Stars(max int) float64 {
+	return math.Floor(mr.Score*float64(max)*2+0.5) / 2
+}
+
+func (p program) MovieRatings() ([]MovieRating, error) {
+	ratings := make([]MovieRating, 0, len(p.Movie.QualityRating))
+	for _, qr := range p.Movie.QualityRating {
+		mr, err := qr.Parse()
+		if err != nil {
+			return []MovieRating{}, err
+		}
+		ratings = append(ratings, mr)
+	}
+	return ratings, nil
+}
+
+// MovieScore is the average score of the ratings, false without rating.
+func (p program) MovieScore() (float64, bool, error) {
+	ratings, err := p.MovieRatings()
+	if err != nil || len(ratings) == 0 {
+		return 0, false, err
+	}
+
+	var total float64
+	for _, mr := range ratings {
+		total += mr.Score
+	}
+
+	return total / float64(len(ratings)), true, nil
+}
+
+var errNoMovieYear = errors.New("no movie year")
+
+func (p program) MovieYear() (int, error) {
+	if p.Movie.Year == "" {
+		return 0, errNoMovieYear
+	}
+
+	year, err := strconv.Atoi(strings.TrimSpace(p.Movie.Year))
+	if err != nil {
+		return 0, fmt.Errorf("invalid movie year: %q", p.Movie.Year)
+	}
+
+	return year, nil
+}
+
+func (p program) MovieDuration() time.Duration {
+	return time.Duration(p.Movie.Duration) * time.Second
+}
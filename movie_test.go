@@ -0,0 +1,64 @@
+package schedulesdirect
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProgramMovieRatings(t *testing.T) {
+	p, err := JsonToProgram([]byte(`{"programID":"MV000123450000","movie":{"duration":5400,"year":"1994","qualityRating":[{"ratingsBody":"Gracenote","rating":"3.5","minRating":"1","maxRating":"4","increment":".5"},{"ratingsBody":"body2","rating":"8","maxRating":"10"}]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ratings, err := p.MovieRatings()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ratings) != 2 {
+		t.Fatalf("len(ratings) != 2: %d", len(ratings))
+	}
+	if ratings[0].Score != 2.5/3 || ratings[0].Increment != 0.5 {
+		t.Fatalf("ratings[0] doesn't match: %v", ratings[0])
+	}
+	if ratings[1].Score != 0.8 || ratings[1].Stars(5) != 4 {
+		t.Fatalf("ratings[1] doesn't match: %v", ratings[1])
+	}
+	if s := ratings[0].Stars(5); s != 4 {
+		t.Fatalf("ratings[0].Stars(5) != 4: %v", s)
+	}
+
+	year, err := p.MovieYear()
+	if err != nil || year != 1994 {
+		t.Fatalf("year != 1994: %d %v", year, err)
+	}
+
+	if p.MovieDuration() != 90*time.Minute {
+		t.Fatalf("p.MovieDuration() != 90m: %s", p.MovieDuration())
+	}
+}
+
+func TestQualityRatingParseFails(t *testing.T) {
+	tests := []qualityRating{
+		{RatingsBody: "body1", Rating: "three", MaxRating: "4"},
+		{RatingsBody: "body1", Rating: "3", MaxRating: ""},
+		{RatingsBody: "body1", Rating: "5", MaxRating: "4"},
+		{RatingsBody: "body1", Rating: "3", MinRating: "4", MaxRating: "4"},
+		{RatingsBody: "body1", Rating: "3", MaxRating: "4", Increment: "half"},
+		{RatingsBody: "body1", Rating: "NaN", MaxRating: "4"},
+		{RatingsBody: "body1", Rating: "3", MaxRating: "NaN"},
+		{RatingsBody: "body1", Rating: "3", MaxRating: "+Inf"},
+		{RatingsBody: "body1", Rating: "3", MinRating: "-Inf", MaxRating: "4"},
+	}
+
+	for _, qr := range tests {
+		if _, err := qr.Parse(); err == nil {
+			t.Fatalf("%v should fail", qr)
+		}
+	}
+
+	if _, err := (program{}).MovieYear(); err == nil {
+		t.Fatal("MovieYear() should fail without year")
+	}
+}
@@ -0,0 +1,189 @@
+package schedulesdirect
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// lower-cased roles to XMLTV credit types
+var xmltvCredits = map[string]string{
+	"actor":              "actor",
+	"voice":              "actor",
+	"guest star":         "guest",
+	"guest":              "guest",
+	"guest voice":        "guest",
+	"musical guest":      "guest",
+	"contestant":         "guest",
+	"director":           "director",
+	"writer":             "writer",
+	"screenwriter":       "writer",
+	"writer (story)":     "writer",
+	"producer":           "producer",
+	"executive producer": "producer",
+	"host":               "presenter",
+	"anchor":             "presenter",
+	"presenter":          "presenter",
+	"narrator":           "commentator",
+	"commentator":        "commentator",
+	"composer":           "composer",
+	"editor":             "editor",
+}
+
+// XMLTVCredit maps a role to an XMLTV credit type, empty when unknown.
+func XMLTVCredit(role string) string {
+	r := strings.ToLower(strings.TrimSpace(role))
+	if c, ok := xmltvCredits[r]; ok {
+		return c
+	}
+
+	// "Co-Producer", "Head Writer"...
+	for _, c := range []string{"producer", "writer", "director"} {
+		if strings.Contains(r, c) {
+			return c
+		}
+	}
+
+	return ""
+}
+
+// Credit is a person of the cast or crew of a program. Billing is
+// BillingOrder as a number, math.MaxInt32 when missing.
+type Credit struct {
+	ProgramID     string
+	PersonId      string
+	NameId        string
+	Name          string
+	Role          string
+	CharacterName string
+	Billing       int
+	XMLTV         string
+}
+
+func newCredit(programID string, p person, characterName string) Credit {
+	billing, err := strconv.Atoi(strings.TrimSpace(p.BillingOrder))
+	if err != nil {
+		billing = math.MaxInt32
+	}
+
+	return Credit{
+		ProgramID:     programID,
+		PersonId:      p.PersonId,
+		NameId:        p.NameId,
+		Name:          p.Name,
+		Role:          p.Role,
+		CharacterName: characterName,
+		Billing:       billing,
+		XMLTV:         XMLTVCredit(p.Role),
+	}
+}
+
+// key identifies a person, the personId when there's one.
+func (c Credit) key() string {
+	if c.PersonId != "" {
+		return c.PersonId
+	}
+	return c.NameId
+}
+
+// Credits returns the cast and the crew sorted by billing order.
+func (p program) Credits() []Credit {
+	credits := make([]Credit, 0, len(p.Cast)+len(p.Crew))
+	for _, c := range p.Cast {
+		credits = append(credits, newCredit(p.ProgramID, c.person, c.CharacterName))
+	}
+	for _, c := range p.Crew {
+		credits = append(credits, newCredit(p.ProgramID, c, ""))
+	}
+
+	sort.Stable(creditsByBilling(credits))
+
+	return credits
+}
+
+// PersonIndex finds the programs and airings of a guide by person.
+type PersonIndex struct {
+	credits map[string][]Credit
+	airings map[string][]*Airing
+}
+
+func NewPersonIndex(g *Guide) *PersonIndex {
+	pi := &PersonIndex{
+		credits: make(map[string][]Credit),
+		airings: make(map[string][]*Airing),
+	}
+
+	for _, p := range g.programs {
+		for _, c := range p.Credits() {
+			if k := c.key(); k != "" {
+				pi.credits[k] = append(pi.credits[k], c)
+			}
+		}
+	}
+
+	for _, airings := range g.stations {
+		for _, a := range airings {
+			pi.airings[a.ProgramID] = append(pi.airings[a.ProgramID], a)
+		}
+	}
+
+	for _, airings := range pi.airings {
+		sort.Sort(airingsByStart(airings))
+	}
+
+	for _, credits := range pi.credits {
+		sort.Sort(creditsByProgram(credits))
+	}
+
+	return pi
+}
+
+// Filmography returns the credits of a person (personId or nameId) in the
+// programs of the guide, sorted by programID.
+func (pi *PersonIndex) Filmography(personID string) []Credit {
+	return append([]Credit{}, pi.credits[personID]...)
+}
+
+// Airings returns the airings overlapping [from, to) featuring a person,
+// sorted by start.
+func (pi *PersonIndex) Airings(personID string, from, to time.Time) []*Airing {
+	seen := make(map[string]bool)
+	result := []*Airing{}
+
+	for _, c := range pi.credits[personID] {
+		// a person can have several roles in a program
+		if seen[c.ProgramID] {
+			continue
+		}
+		seen[c.ProgramID] = true
+
+		for _, a := range pi.airings[c.ProgramID] {
+			if a.overlaps(from, to) {
+				result = append(result, a)
+			}
+		}
+	}
+
+	sort.Sort(airingsByStart(result))
+
+	return result
+}
+
+type creditsByBilling []Credit
+
+func (c creditsByBilling) Len() int           { return len(c) }
+func (c creditsByBilling) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+func (c creditsByBilling) Less(i, j int) bool { return c[i].Billing < c[j].Billing }
+
+type creditsByProgram []Credit
+
+func (c creditsByProgram) Len() int      { return len(c) }
+func (c creditsByProgram) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+func (c creditsByProgram) Less(i, j int) bool {
+	if c[i].ProgramID != c[j].ProgramID {
+		return c[i].ProgramID < c[j].ProgramID
+	}
+	return c[i].Billing < c[j].Billing
+}
@@ -0,0 +1,65 @@
+package schedulesdirect
+
+import (
+	"testing"
+)
+
+func TestXMLTVCredit(t *testing.T) {
+	tests := map[string]string{
+		"Actor":              "actor",
+		"Guest Star":         "guest",
+		"Executive Producer": "producer",
+		"Co-Producer":        "producer",
+		"Host":               "presenter",
+		"Director":           "director",
+		"Writer (story)":     "writer",
+		"Grip":               "",
+	}
+
+	for in, expect := range tests {
+		if c := XMLTVCredit(in); c != expect {
+			t.Fatalf("XMLTVCredit(%q) (%s) != %s", in, c, expect)
+		}
+	}
+}
+
+func TestPersonIndex(t *testing.T) {
+	p1, err := JsonToProgram([]byte(`{"programID":"EP000000010001","cast":[{"billingOrder":"10","name":"name2","personId":"person2","role":"Guest Star","characterName":"character2"},{"billingOrder":"2","name":"name1","personId":"person1","role":"Actor","characterName":"character1"}],"crew":[{"billingOrder":"1","name":"name3","personId":"person3","role":"Director"},{"name":"name1","personId":"person1","role":"Writer"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	credits := p1.Credits()
+	if len(credits) != 4 {
+		t.Fatalf("len(credits) != 4: %d", len(credits))
+	}
+	if credits[0].PersonId != "person3" || credits[1].PersonId != "person1" || credits[2].PersonId != "person2" || credits[3].Role != "Writer" {
+		t.Fatalf("credits aren't sorted by billing order: %v", credits)
+	}
+	if credits[2].XMLTV != "guest" || credits[2].CharacterName != "character2" {
+		t.Fatalf("credits[2] doesn't match: %v", credits[2])
+	}
+
+	p2 := program{ProgramID: "MV000000020000", Cast: []cast{{person: person{PersonId: "person1", Role: "Actor", BillingOrder: "1"}}}}
+
+	s, err := JsonToSchedules([]byte(`{"stationID":"10001","programs":[{"airDateTime":"2014-07-30T02:00:00Z","duration":7200,"programID":"MV000000020000"},{"airDateTime":"2014-07-30T01:00:00Z","duration":3600,"programID":"EP000000010001"},{"airDateTime":"2014-07-31T01:00:00Z","duration":3600,"programID":"EP000000010001"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pi := NewPersonIndex(NewGuide([]schedule{s}, []program{p1, p2}))
+
+	films := pi.Filmography("person1")
+	if len(films) != 3 || films[0].ProgramID != "EP000000010001" || films[2].ProgramID != "MV000000020000" {
+		t.Fatalf("films don't match: %v", films)
+	}
+
+	airings := pi.Airings("person1", testTime("2014-07-30T00:00:00Z"), testTime("2014-07-31T00:00:00Z"))
+	if len(airings) != 2 || airings[0].ProgramID != "EP000000010001" || airings[1].ProgramID != "MV000000020000" {
+		t.Fatalf("airings don't match: %v", airings)
+	}
+
+	if len(pi.Airings("person9", testTime("2014-07-30T00:00:00Z"), testTime("2014-07-31T00:00:00Z"))) != 0 {
+		t.Fatal("person9 shouldn't have airings")
+	}
+}
@@ -0,0 +1,109 @@
+package schedulesdirect
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+type ProgramKind string
+
+const (
+	ProgramEpisode ProgramKind = "EP"
+	ProgramShow    ProgramKind = "SH"
+	ProgramMovie   ProgramKind = "MV"
+	ProgramSports  ProgramKind = "SP"
+)
+
+// ProgramID is a parsed programID like EP012345670003: a kind, an 8 digits
+// series root and a 4 digits episode part.
+type ProgramID struct {
+	Kind    ProgramKind
+	Root    string
+	Episode string
+}
+
+func ParseProgramID(programID string) (ProgramID, error) {
+	if len(programID) != 14 {
+		return ProgramID{}, fmt.Errorf("invalid programID: %q", programID)
+	}
+
+	p := ProgramID{
+		Kind:    ProgramKind(programID[:2]),
+		Root:    programID[2:10],
+		Episode: programID[10:],
+	}
+
+	if !p.Valid() {
+		return ProgramID{}, fmt.Errorf("invalid programID: %q", programID)
+	}
+
+	return p, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func (p ProgramID) Valid() bool {
+	switch p.Kind {
+	case ProgramEpisode, ProgramShow, ProgramMovie, ProgramSports:
+	default:
+		return false
+	}
+
+	return len(p.Root) == 8 && isDigits(p.Root) && len(p.Episode) == 4 && isDigits(p.Episode)
+}
+
+func (p ProgramID) String() string {
+	return string(p.Kind) + p.Root + p.Episode
+}
+
+// EpisodeNumber is the episode part as a number, 0 for a show.
+func (p ProgramID) EpisodeNumber() int {
+	n, _ := strconv.Atoi(p.Episode)
+	return n
+}
+
+// SeriesID returns the SH programID of the series, movies don't have one.
+func (p ProgramID) SeriesID() (string, bool) {
+	if p.Kind == ProgramMovie || !p.Valid() {
+		return "", false
+	}
+	return string(ProgramShow) + p.Root + "0000", true
+}
+
+// GroupBySeries groups programs by their SH programID. Programs without
+// series (movies and invalid programIDs) are ignored. Each group is sorted by
+// programID.
+func GroupBySeries(programs []program) map[string][]program {
+	groups := make(map[string][]program)
+
+	for _, p := range programs {
+		id, err := ParseProgramID(p.ProgramID)
+		if err != nil {
+			continue
+		}
+
+		if seriesID, ok := id.SeriesID(); ok {
+			groups[seriesID] = append(groups[seriesID], p)
+		}
+	}
+
+	for _, g := range groups {
+		sort.Sort(programsByID(g))
+	}
+
+	return groups
+}
+
+type programsByID []program
+
+func (p programsByID) Len() int           { return len(p) }
+func (p programsByID) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+func (p programsByID) Less(i, j int) bool { return p[i].ProgramID < p[j].ProgramID }
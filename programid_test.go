@@ -0,0 +1,58 @@
+package schedulesdirect
+
+import (
+	"testing"
+)
+
+func TestParseProgramID(t *testing.T) {
+	p, err := ParseProgramID("EP012345670003")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p.Kind != ProgramEpisode || p.Root != "01234567" || p.EpisodeNumber() != 3 {
+		t.Fatalf("p doesn't match: %v", p)
+	}
+	if p.String() != "EP012345670003" {
+		t.Fatalf(`p.String() != "EP012345670003": %s`, p)
+	}
+
+	seriesID, ok := p.SeriesID()
+	if !ok || seriesID != "SH012345670000" {
+		t.Fatalf(`seriesID != "SH012345670000": %s`, seriesID)
+	}
+
+	p, err = ParseProgramID("MV000123450000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := p.SeriesID(); ok {
+		t.Fatal("a movie shouldn't have a series")
+	}
+
+	for _, in := range []string{"", "program1", "XX012345670003", "EP0123456700031", "EP01234A670003"} {
+		if _, err := ParseProgramID(in); err == nil {
+			t.Fatalf("ParseProgramID(%q) should fail", in)
+		}
+	}
+}
+
+func TestGroupBySeries(t *testing.T) {
+	groups := GroupBySeries([]program{
+		{ProgramID: "EP012345670002"},
+		{ProgramID: "EP012345670001"},
+		{ProgramID: "SH012345670000"},
+		{ProgramID: "SP076543210012"},
+		{ProgramID: "MV000123450000"},
+		{ProgramID: "program1"},
+	})
+
+	if len(groups) != 2 {
+		t.Fatalf("len(groups) != 2: %v", groups)
+	}
+
+	g := groups["SH012345670000"]
+	if len(g) != 3 || g[0].ProgramID != "EP012345670001" || g[2].ProgramID != "SH012345670000" {
+		t.Fatalf("group doesn't match: %v", g)
+	}
+}
@@ -0,0 +1,130 @@
+package schedulesdirect
+
+import (
+	"strings"
+)
+
+// Property is an audio or video property of an airing. The unknown values
+// are kept as is.
+type Property string
+
+// audio properties
+const (
+	AudioAtmos     Property = "atmos"
+	AudioCC        Property = "cc"
+	AudioDD        Property = "dd"
+	AudioDD51      Property = "dd 5.1"
+	AudioDolby     Property = "dolby"
+	AudioDubbed    Property = "dubbed"
+	AudioDVS       Property = "dvs"
+	AudioSAP       Property = "sap"
+	AudioStereo    Property = "stereo"
+	AudioSubtitled Property = "subtitled"
+)
+
+// video properties
+const (
+	Video3D        Property = "3d"
+	VideoEnhanced  Property = "enhanced"
+	VideoHDTV      Property = "hdtv"
+	VideoLetterbox Property = "letterbox"
+	VideoSDTV      Property = "sdtv"
+	VideoUHDTV     Property = "uhdtv"
+)
+
+// airing properties, found in both lists
+const (
+	PropertyLive     Property = "live"
+	PropertyTape     Property = "tape"
+	PropertyPremiere Property = "premiere"
+	PropertyFinale   Property = "finale"
+)
+
+// Properties keeps the values and their order, it marshals back to the same
+// JSON array of strings.
+type Properties []Property
+
+// Has is case insensitive, the service isn't consistent.
+func (p Properties) Has(property Property) bool {
+	for _, v := range p {
+		if strings.EqualFold(string(v), string(property)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (sp scheduleProgram) hasProperty(properties ...Property) bool {
+	for _, p := range properties {
+		if sp.AudioProperties.Has(p) || sp.VideoProperties.Has(p) {
+			return true
+		}
+	}
+	return false
+}
+
+func (sp scheduleProgram) IsHD() bool {
+	return sp.VideoProperties.Has(VideoHDTV) || sp.VideoProperties.Has(VideoUHDTV)
+}
+
+func (sp scheduleProgram) IsUHD() bool {
+	return sp.VideoProperties.Has(VideoUHDTV)
+}
+
+func (sp scheduleProgram) Is3D() bool {
+	return sp.VideoProperties.Has(Video3D)
+}
+
+func (sp scheduleProgram) IsLetterbox() bool {
+	return sp.VideoProperties.Has(VideoLetterbox)
+}
+
+func (sp scheduleProgram) HasClosedCaptions() bool {
+	return sp.AudioProperties.Has(AudioCC)
+}
+
+func (sp scheduleProgram) HasSAP() bool {
+	return sp.AudioProperties.Has(AudioSAP)
+}
+
+func (sp scheduleProgram) HasDescribedVideo() bool {
+	return sp.AudioProperties.Has(AudioDVS)
+}
+
+func (sp scheduleProgram) IsStereo() bool {
+	return sp.AudioProperties.Has(AudioStereo)
+}
+
+// HasSurround is true for Dolby Digital 5.1 and Atmos.
+func (sp scheduleProgram) HasSurround() bool {
+	return sp.AudioProperties.Has(AudioDD51) || sp.AudioProperties.Has(AudioAtmos)
+}
+
+func (sp scheduleProgram) IsSubtitled() bool {
+	return sp.AudioProperties.Has(AudioSubtitled)
+}
+
+func (sp scheduleProgram) IsDubbed() bool {
+	return sp.AudioProperties.Has(AudioDubbed)
+}
+
+func (sp scheduleProgram) IsLive() bool {
+	return sp.hasProperty(PropertyLive)
+}
+
+// IsTapeDelayed also looks at the syndication type.
+func (sp scheduleProgram) IsTapeDelayed() bool {
+	return sp.hasProperty(PropertyTape) || strings.Contains(strings.ToLower(sp.Syndication.Type), "tape")
+}
+
+func (sp scheduleProgram) IsPremiere() bool {
+	return sp.hasProperty(PropertyPremiere)
+}
+
+func (sp scheduleProgram) IsFinale() bool {
+	return sp.hasProperty(PropertyFinale)
+}
+
+func (sp scheduleProgram) IsNew() bool {
+	return sp.New
+}
@@ -0,0 +1,33 @@
+package schedulesdirect
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestScheduleProgramProperties(t *testing.T) {
+	s, err := JsonToSchedules([]byte(`{"stationID":"10001","programs":[{"airDateTime":"2014-07-30T00:00:00Z","duration":1800,"programID":"program1","audioProperties":["cc","DD 5.1","ap1"],"videoProperties":["hdtv","premiere"],"new":true}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sp := s.Programs[0]
+
+	if !sp.IsHD() || sp.IsUHD() || !sp.HasClosedCaptions() || !sp.HasSurround() || !sp.IsPremiere() || !sp.IsNew() {
+		t.Fatalf("flags don't match: %v %v", sp.AudioProperties, sp.VideoProperties)
+	}
+	if sp.IsLive() || sp.IsStereo() || sp.HasSAP() {
+		t.Fatalf("flags don't match: %v %v", sp.AudioProperties, sp.VideoProperties)
+	}
+
+	if !sp.AudioProperties.Has("ap1") {
+		t.Fatal("unknown property ap1 was dropped")
+	}
+
+	data, err := json.Marshal(sp.AudioProperties)
+	if err != nil {
+		t.Fatal(err)
+	} else if string(data) != `["cc","DD 5.1","ap1"]` {
+		t.Fatalf("data doesn't match: %s", data)
+	}
+}
@@ -0,0 +1,225 @@
+package schedulesdirect
+
+import (
+	"sort"
+	"strings"
+)
+
+type ratingBody struct {
+	Country string
+
+	// normalized code to minimum age
+	Ages map[string]int
+}
+
+var ratingBodies = map[string]ratingBody{
+	"USA Parental Rating": {"USA", map[string]int{
+		"TVY": 0, "TVY7": 7, "TVG": 0, "TVPG": 10, "TV14": 14, "TVMA": 17,
+	}},
+	"Motion Picture Association of America": {"USA", map[string]int{
+		"G": 0, "PG": 10, "PG13": 13, "R": 17, "NC17": 18,
+	}},
+	"Canadian Parental Rating": {"CAN", map[string]int{
+		"E": 0, "C": 0, "C8": 8, "G": 0, "PG": 10, "14+": 14, "18+": 18,
+	}},
+	"Régie du cinéma du Québec": {"CAN", map[string]int{
+		"G": 0, "8+": 8, "13+": 13, "16+": 16, "18+": 18,
+	}},
+	"British Board of Film Classification": {"GBR", map[string]int{
+		"U": 0, "PG": 8, "12": 12, "12A": 12, "15": 15, "18": 18, "R18": 18,
+	}},
+	"Freiwillige Selbstkontrolle der Filmwirtschaft": {"DEU", map[string]int{
+		"0": 0, "6": 6, "12": 12, "16": 16, "18": 18,
+	}},
+}
+
+// Rating is a content rating of an airing. MinAge is -1 when the body or the
+// code isn't known.
+type Rating struct {
+	Body    string
+	Code    string
+	Country string
+	MinAge  int
+}
+
+func (r Rating) Known() bool {
+	return r.MinAge >= 0
+}
+
+func normalizeRatingCode(code string) string {
+	c := strings.ToUpper(code)
+	c = strings.NewReplacer("-", "", " ", "").Replace(c)
+	return strings.TrimPrefix(c, "FSK")
+}
+
+func ParseRating(body, code string) Rating {
+	r := Rating{Body: body, Code: code, MinAge: -1}
+
+	b, ok := ratingBodies[body]
+	if !ok {
+		return r
+	}
+
+	r.Country = b.Country
+	if age, ok := b.Ages[normalizeRatingCode(code)]; ok {
+		r.MinAge = age
+	}
+
+	return r
+}
+
+func (sp scheduleProgram) Ratings() []Rating {
+	ratings := make([]Rating, 0, len(sp.ContentRating))
+	for _, cr := range sp.ContentRating {
+		ratings = append(ratings, ParseRating(cr.Body, cr.Code))
+	}
+	return ratings
+}
+
+// MinAge returns the highest known minimum age for a country (alpha-3), false
+// when the airing isn't rated for it.
+func (sp scheduleProgram) MinAge(country string) (int, bool) {
+	age, found := -1, false
+	for _, r := range sp.Ratings() {
+		if r.Country == country && r.Known() && r.MinAge > age {
+			age, found = r.MinAge, true
+		}
+	}
+	return age, found
+}
+
+type Advisory int
+
+const (
+	AdvisoryOther Advisory = iota
+	AdvisoryViolence
+	AdvisoryFantasyViolence
+	AdvisoryLanguage
+	AdvisorySexualContent
+	AdvisoryNudity
+	AdvisoryDialogue
+	AdvisoryAdultSituations
+	AdvisoryRape
+)
+
+var advisoryNames = map[Advisory]string{
+	AdvisoryOther:           "Other",
+	AdvisoryViolence:        "Violence",
+	AdvisoryFantasyViolence: "Fantasy Violence",
+	AdvisoryLanguage:        "Language",
+	AdvisorySexualContent:   "Sexual Content",
+	AdvisoryNudity:          "Nudity",
+	AdvisoryDialogue:        "Suggestive Dialogue",
+	AdvisoryAdultSituations: "Adult Situations",
+	AdvisoryRape:            "Rape",
+}
+
+func (a Advisory) String() string {
+	return advisoryNames[a]
+}
+
+// ParseAdvisory maps an advisory like "Graphic Violence" or the "V" of a TV
+// rating to an Advisory, AdvisoryOther when unknown.
+func ParseAdvisory(advisory string) Advisory {
+	a := strings.ToLower(strings.TrimSpace(advisory))
+
+	switch a {
+	case "v":
+		return AdvisoryViolence
+	case "fv":
+		return AdvisoryFantasyViolence
+	case "l":
+		return AdvisoryLanguage
+	case "s":
+		return AdvisorySexualContent
+	case "d":
+		return AdvisoryDialogue
+	}
+
+	switch {
+	case strings.Contains(a, "fantasy violence"):
+		return AdvisoryFantasyViolence
+	case strings.Contains(a, "violence"):
+		return AdvisoryViolence
+	case strings.Contains(a, "language"):
+		return AdvisoryLanguage
+	case strings.Contains(a, "rape"):
+		return AdvisoryRape
+	case strings.Contains(a, "sex"):
+		return AdvisorySexualContent
+	case strings.Contains(a, "nudity"):
+		return AdvisoryNudity
+	case strings.Contains(a, "dialog"):
+		return AdvisoryDialogue
+	case strings.Contains(a, "adult situations"):
+		return AdvisoryAdultSituations
+	default:
+		return AdvisoryOther
+	}
+}
+
+// Advisories returns the sorted unique advisories of all rating bodies.
+func (sp scheduleProgram) Advisories() []Advisory {
+	seen := make(map[Advisory]bool)
+	advisories := []Advisory{}
+
+	for _, values := range sp.ContentAdvisory {
+		for _, v := range values {
+			a := ParseAdvisory(v)
+			if !seen[a] {
+				seen[a] = true
+				advisories = append(advisories, a)
+			}
+		}
+	}
+
+	sort.Sort(advisoryList(advisories))
+
+	return advisories
+}
+
+func (sp scheduleProgram) HasAdvisory(advisory Advisory) bool {
+	for _, a := range sp.Advisories() {
+		if a == advisory {
+			return true
+		}
+	}
+	return false
+}
+
+type advisoryList []Advisory
+
+func (a advisoryList) Len() int           { return len(a) }
+func (a advisoryList) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a advisoryList) Less(i, j int) bool { return a[i] < a[j] }
+
+// ParentalFilter hides the airings rated above MaxAge in Country, see
+// NormalizeCountry. Airings not rated for Country are hidden unless AllowUnrated.
+type ParentalFilter struct {
+	Country      string
+	MaxAge       int
+	AllowUnrated bool
+}
+
+func (f ParentalFilter) Allowed(sp scheduleProgram) bool {
+	country, err := NormalizeCountry(f.Country)
+	if err != nil {
+		return false
+	}
+
+	age, ok := sp.MinAge(country)
+	if !ok {
+		return f.AllowUnrated
+	}
+	return age <= f.MaxAge
+}
+
+func (f ParentalFilter) Filter(airings []*Airing) []*Airing {
+	result := []*Airing{}
+	for _, a := range airings {
+		if f.Allowed(a.scheduleProgram) {
+			result = append(result, a)
+		}
+	}
+	return result
+}
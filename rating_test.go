@@ -0,0 +1,70 @@
+package schedulesdirect
+
+import (
+	"testing"
+)
+
+func TestParseRating(t *testing.T) {
+	tests := []struct {
+		body   string
+		code   string
+		expect int
+	}{
+		{"USA Parental Rating", "TV14", 14},
+		{"USA Parental Rating", "TV-MA", 17},
+		{"Motion Picture Association of America", "PG-13", 13},
+		{"Canadian Parental Rating", "14+", 14},
+		{"Freiwillige Selbstkontrolle der Filmwirtschaft", "FSK 16", 16},
+		{"USA Parental Rating", "XYZ", -1},
+		{"body1", "code1", -1},
+	}
+
+	for _, test := range tests {
+		r := ParseRating(test.body, test.code)
+		if r.MinAge != test.expect {
+			t.Fatalf("ParseRating(%s, %s).MinAge (%d) != %d", test.body, test.code, r.MinAge, test.expect)
+		}
+	}
+}
+
+func TestParseAdvisory(t *testing.T) {
+	tests := map[string]Advisory{
+		"Graphic Violence":      AdvisoryViolence,
+		"FV":                    AdvisoryFantasyViolence,
+		"Adult Language":        AdvisoryLanguage,
+		"Strong Sexual Content": AdvisorySexualContent,
+		"Brief Nudity":          AdvisoryNudity,
+		"Adult Situations":      AdvisoryAdultSituations,
+		"stuff1":                AdvisoryOther,
+	}
+
+	for in, expect := range tests {
+		if a := ParseAdvisory(in); a != expect {
+			t.Fatalf("ParseAdvisory(%q) (%s) != %s", in, a, expect)
+		}
+	}
+}
+
+func TestParentalFilter(t *testing.T) {
+	s, err := JsonToSchedules([]byte(`{"stationID":"10001","programs":[{"airDateTime":"2014-07-30T00:00:00Z","duration":1800,"programID":"program1","contentRating":[{"body":"USA Parental Rating","code":"TVPG"},{"body":"Canadian Parental Rating","code":"14+"}],"contentAdvisory":{"USA Parental Rating":["Language","Violence"]}},{"airDateTime":"2014-07-30T00:30:00Z","duration":1800,"programID":"program2","contentRating":[{"body":"USA Parental Rating","code":"TVMA"}]},{"airDateTime":"2014-07-30T01:00:00Z","duration":1800,"programID":"program3"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	advisories := s.Programs[0].Advisories()
+	if len(advisories) != 2 || advisories[0] != AdvisoryViolence || advisories[1] != AdvisoryLanguage {
+		t.Fatalf("advisories don't match: %v", advisories)
+	}
+
+	airings := NewGuide([]schedule{s}, []program{}).Airings("10001")
+
+	allowed := ParentalFilter{Country: "USA", MaxAge: 14}.Filter(airings)
+	if len(allowed) != 1 || allowed[0].ProgramID != "program1" {
+		t.Fatalf("allowed doesn't match: %v", allowed)
+	}
+
+	allowed = ParentalFilter{Country: "Canada", MaxAge: 13, AllowUnrated: true}.Filter(airings)
+	if len(allowed) != 2 || allowed[0].ProgramID != "program2" {
+		t.Fatalf("allowed doesn't match: %v", allowed)
+	}
+}
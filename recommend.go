@@ -0,0 +1,188 @@
+package schedulesdirect
+
+import (
+	"sort"
+	"time"
+)
+
+// RecommendationGraph is the directed graph of the Recommendations of
+// programs, by programID.
+type RecommendationGraph struct {
+	edges   map[string][]string
+	reverse map[string][]string
+	titles  map[string]string
+}
+
+func NewRecommendationGraph(programs []program) *RecommendationGraph {
+	rg := &RecommendationGraph{
+		edges:   make(map[string][]string),
+		reverse: make(map[string][]string),
+		titles:  make(map[string]string),
+	}
+
+	seen := make(map[[2]string]bool)
+
+	for _, p := range programs {
+		if t := p.Titles["title120"]; t != "" {
+			rg.titles[p.ProgramID] = t
+		}
+
+		for _, r := range p.Recommendations {
+			if r.ProgramID == "" || r.ProgramID == p.ProgramID {
+				continue
+			}
+			if _, ok := rg.titles[r.ProgramID]; !ok && r.Title120 != "" {
+				rg.titles[r.ProgramID] = r.Title120
+			}
+
+			edge := [2]string{p.ProgramID, r.ProgramID}
+			if seen[edge] {
+				continue
+			}
+			seen[edge] = true
+
+			rg.edges[p.ProgramID] = append(rg.edges[p.ProgramID], r.ProgramID)
+			rg.reverse[r.ProgramID] = append(rg.reverse[r.ProgramID], p.ProgramID)
+		}
+	}
+
+	return rg
+}
+
+// Recommendations returns the programIDs recommended by a program.
+func (rg *RecommendationGraph) Recommendations(programID string) []string {
+	return append([]string{}, rg.edges[programID]...)
+}
+
+// RecommendedBy returns the programIDs recommending a program.
+func (rg *RecommendationGraph) RecommendedBy(programID string) []string {
+	return append([]string{}, rg.reverse[programID]...)
+}
+
+func (rg *RecommendationGraph) Title(programID string) string {
+	return rg.titles[programID]
+}
+
+// Suggestion is a recommended program airing in the window. Because lists the
+// recorded programs recommending it.
+type Suggestion struct {
+	ProgramID string
+	Title     string
+	Score     float64
+	Because   []string
+	Airings   []*Airing
+}
+
+// seriesOrSelf returns the SH programID of episodes and sports events, the
+// programID otherwise.
+func seriesOrSelf(programID string) string {
+	if id, err := ParseProgramID(programID); err == nil && id.Kind != ProgramMovie {
+		if seriesID, ok := id.SeriesID(); ok {
+			return seriesID
+		}
+	}
+	return programID
+}
+
+// Suggest ranks the programs recommended by a recording history (programIDs)
+// that air in [from, to) on the stations of the guide. A direct
+// recommendation scores 1 and a recommendation of a recommendation 0.5, the
+// episodes of a series count as the series. Programs of the history are
+// never suggested.
+func (rg *RecommendationGraph) Suggest(g *Guide, history []string, from, to time.Time) []Suggestion {
+	recorded := make(map[string]bool)
+	for _, id := range history {
+		recorded[id] = true
+		recorded[seriesOrSelf(id)] = true
+	}
+
+	type score struct {
+		value   float64
+		because map[string]bool
+	}
+	scores := make(map[string]*score)
+
+	add := func(target, because string, value float64) {
+		key := seriesOrSelf(target)
+		if recorded[target] || recorded[key] {
+			return
+		}
+
+		s, ok := scores[key]
+		if !ok {
+			s = &score{because: make(map[string]bool)}
+			scores[key] = s
+		}
+		s.value += value
+		s.because[because] = true
+	}
+
+	for _, h := range history {
+		sources := []string{h}
+		if s := seriesOrSelf(h); s != h {
+			sources = append(sources, s)
+		}
+
+		for _, source := range sources {
+			for _, r := range rg.edges[source] {
+				add(r, h, 1)
+				for _, r2 := range rg.edges[r] {
+					add(r2, h, 0.5)
+				}
+			}
+		}
+	}
+
+	// upcoming airings by programID and series
+	airings := make(map[string][]*Airing)
+	for _, stationID := range g.StationIDs() {
+		for _, a := range g.Between(stationID, from, to) {
+			key := seriesOrSelf(a.ProgramID)
+			if _, ok := scores[key]; ok {
+				airings[key] = append(airings[key], a)
+			}
+		}
+	}
+
+	suggestions := []Suggestion{}
+
+	for key, s := range scores {
+		a := airings[key]
+		if len(a) == 0 {
+			continue
+		}
+
+		sort.Sort(airingsByStart(a))
+
+		because := make([]string, 0, len(s.because))
+		for id := range s.because {
+			because = append(because, id)
+		}
+		sort.Strings(because)
+
+		title := rg.titles[key]
+		if title == "" {
+			title = rg.titles[a[0].ProgramID]
+		}
+
+		suggestions = append(suggestions, Suggestion{key, title, s.value, because, a})
+	}
+
+	sort.Sort(suggestionsByScore(suggestions))
+
+	return suggestions
+}
+
+type suggestionsByScore []Suggestion
+
+func (s suggestionsByScore) Len() int      { return len(s) }
+func (s suggestionsByScore) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s suggestionsByScore) Less(i, j int) bool {
+	if s[i].Score != s[j].Score {
+		return s[i].Score > s[j].Score
+	}
+	if ai, aj := s[i].Airings[0].AirDateTime, s[j].Airings[0].AirDateTime; !ai.Equal(aj) {
+		return ai.Before(aj)
+	}
+	return s[i].ProgramID < s[j].ProgramID
+}
@@ -0,0 +1,48 @@
+package schedulesdirect
+
+import (
+	"testing"
+)
+
+func TestRecommendationGraph(t *testing.T) {
+	programs := []program{
+		{ProgramID: "EP000000010001", Titles: map[string]string{"title120": "title1"}},
+		{ProgramID: "SH000000010000", Recommendations: []recommendation{{"SH000000020000", "title2"}, {"MV000000030000", "title3"}, {"SH000000020000", "title2"}}},
+		{ProgramID: "SH000000020000", Recommendations: []recommendation{{"SH000000040000", "title4"}, {"SH000000010000", "title1"}}},
+	}
+
+	rg := NewRecommendationGraph(programs)
+
+	if r := rg.Recommendations("SH000000010000"); len(r) != 2 {
+		t.Fatalf("recommendations don't match: %v", r)
+	}
+	if r := rg.RecommendedBy("SH000000020000"); len(r) != 1 || r[0] != "SH000000010000" {
+		t.Fatalf("recommended by doesn't match: %v", r)
+	}
+
+	s, err := JsonToSchedules([]byte(`{"stationID":"10001","programs":[{"airDateTime":"2014-07-30T01:00:00Z","duration":3600,"programID":"EP000000020005"},{"airDateTime":"2014-07-30T00:00:00Z","duration":3600,"programID":"EP000000040001"},{"airDateTime":"2014-07-30T02:00:00Z","duration":3600,"programID":"EP000000010002"},{"airDateTime":"2014-08-30T02:00:00Z","duration":7200,"programID":"MV000000030000"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewGuide([]schedule{s}, programs)
+
+	suggestions := rg.Suggest(g, []string{"EP000000010001"}, testTime("2014-07-30T00:00:00Z"), testTime("2014-07-31T00:00:00Z"))
+	if len(suggestions) != 2 {
+		t.Fatalf("len(suggestions) != 2: %v", suggestions)
+	}
+
+	if suggestions[0].ProgramID != "SH000000020000" || suggestions[0].Score != 1 || suggestions[0].Title != "title2" {
+		t.Fatalf("suggestions[0] doesn't match: %v", suggestions[0])
+	}
+	if len(suggestions[0].Airings) != 1 || suggestions[0].Airings[0].ProgramID != "EP000000020005" {
+		t.Fatalf("suggestions[0].Airings don't match: %v", suggestions[0].Airings)
+	}
+	if len(suggestions[0].Because) != 1 || suggestions[0].Because[0] != "EP000000010001" {
+		t.Fatalf("suggestions[0].Because doesn't match: %v", suggestions[0].Because)
+	}
+
+	if suggestions[1].ProgramID != "SH000000040000" || suggestions[1].Score != 0.5 {
+		t.Fatalf("suggestions[1] doesn't match: %v", suggestions[1])
+	}
+}
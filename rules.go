@@ -0,0 +1,280 @@
+package schedulesdirect
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Rule selects the airings to record, every field set must match. SeriesID
+// is a SH programID or a series root, Title a regular expression on the
+// title, Keyword is searched in the titles and descriptions, PersonID is a
+// personId or nameId of the cast or crew.
+//
+// TimeFrom and TimeTo are the time of day in Location (UTC when nil), TimeTo
+// can be before TimeFrom to cross midnight. Both at 0 means any time.
+type Rule struct {
+	Name     string
+	Priority int
+
+	SeriesID   string
+	Title      string
+	Keyword    string
+	PersonID   string
+	Categories []GenreCategory
+	NewOnly    bool
+	StationIDs []string
+
+	Days     []time.Weekday
+	TimeFrom time.Duration
+	TimeTo   time.Duration
+	Location *time.Location
+
+	PaddingBefore time.Duration
+	PaddingAfter  time.Duration
+}
+
+type compiledRule struct {
+	*Rule
+	title *regexp.Regexp
+}
+
+// Recording is an airing to record with the padding of its rule.
+type Recording struct {
+	*Airing
+	Rule     string
+	Priority int
+	Start    time.Time
+	End      time.Time
+
+	// the rule and the window of the plan, to look for another airing
+	rule     *compiledRule
+	from, to time.Time
+}
+
+func (r compiledRule) match(a *Airing) bool {
+	p := a.Program
+
+	if r.NewOnly && !a.New {
+		return false
+	}
+
+	if len(r.StationIDs) > 0 && !containsString(r.StationIDs, a.StationID) {
+		return false
+	}
+
+	if r.SeriesID != "" {
+		// a SH programID or its first 10 characters
+		seriesID := seriesOrSelf(a.ProgramID)
+		if seriesID != r.SeriesID && (len(r.SeriesID) != 10 || seriesID != r.SeriesID+"0000") {
+			return false
+		}
+	}
+
+	if !r.matchTime(a.Start()) {
+		return false
+	}
+
+	if r.title == nil && r.Keyword == "" && r.PersonID == "" && len(r.Categories) == 0 {
+		return true
+	}
+
+	// the other criteria need the program details
+	if p == nil {
+		return false
+	}
+
+	if r.title != nil && !r.title.MatchString(p.Titles["title120"]) {
+		return false
+	}
+
+	if r.Keyword != "" && !p.containsKeyword(r.Keyword) {
+		return false
+	}
+
+	if r.PersonID != "" {
+		found := false
+		for _, c := range p.Credits() {
+			if c.PersonId == r.PersonID || c.NameId == r.PersonID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return len(r.Categories) == 0 || p.HasCategory(r.Categories...)
+}
+
+// hasCriteria tells if the rule selects some airings, a rule without criteria
+// would record everything.
+func (r *Rule) hasCriteria() bool {
+	return r.SeriesID != "" || r.Title != "" || r.Keyword != "" || r.PersonID != "" ||
+		len(r.Categories) > 0 || r.NewOnly || len(r.StationIDs) > 0 ||
+		len(r.Days) > 0 || r.TimeFrom != 0 || r.TimeTo != 0
+}
+
+func (r compiledRule) matchTime(t time.Time) bool {
+	loc := r.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	t = t.In(loc)
+
+	if len(r.Days) > 0 {
+		found := false
+		for _, d := range r.Days {
+			if d == t.Weekday() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if r.TimeFrom == 0 && r.TimeTo == 0 {
+		return true
+	}
+
+	tod := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+
+	if r.TimeFrom <= r.TimeTo {
+		return tod >= r.TimeFrom && tod < r.TimeTo
+	}
+	return tod >= r.TimeFrom || tod < r.TimeTo
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (p program) containsKeyword(keyword string) bool {
+	keyword = strings.ToLower(keyword)
+
+	for _, t := range p.Titles {
+		if strings.Contains(strings.ToLower(t), keyword) {
+			return true
+		}
+	}
+	for _, descriptions := range p.Descriptions {
+		for _, d := range descriptions {
+			if strings.Contains(strings.ToLower(d.Description), keyword) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// betterAiring tells if a should be recorded instead of b: HD first, then earlier.
+func betterAiring(a, b *Airing) bool {
+	if a.IsHD() != b.IsHD() {
+		return a.IsHD()
+	}
+	return a.AirDateTime.Before(b.AirDateTime)
+}
+
+// PlanRecordings evaluates the rules against the airings of the guide
+// overlapping [from, to) and returns the recordings sorted by start. When
+// several rules match an airing, the one with the highest Priority wins (the
+// first one on ties).
+//
+// A rule needs at least one criteria, PlanRecordings fails otherwise.
+//
+// Each programID is recorded once, HD and then earlier airings are preferred,
+// and the programIDs of recorded are skipped. SH programIDs (shows without
+// episode information, like the news) aren't deduplicated.
+func PlanRecordings(g *Guide, rules []Rule, from, to time.Time, recorded []string) ([]Recording, error) {
+	compiled := make([]compiledRule, len(rules))
+	for i := range rules {
+		if !rules[i].hasCriteria() {
+			return []Recording{}, fmt.Errorf("rule %q has no criteria", rules[i].Name)
+		}
+
+		compiled[i] = compiledRule{Rule: &rules[i]}
+		if rules[i].Title != "" {
+			re, err := regexp.Compile(rules[i].Title)
+			if err != nil {
+				return []Recording{}, err
+			}
+			compiled[i].title = re
+		}
+	}
+
+	skip := make(map[string]bool, len(recorded))
+	for _, id := range recorded {
+		skip[id] = true
+	}
+
+	best := make(map[string]Recording)
+	var shows []Recording
+
+	for _, stationID := range g.StationIDs() {
+		for _, a := range g.Between(stationID, from, to) {
+			if skip[a.ProgramID] {
+				continue
+			}
+
+			var rule *compiledRule
+			for i := range compiled {
+				if compiled[i].match(a) && (rule == nil || compiled[i].Priority > rule.Priority) {
+					rule = &compiled[i]
+				}
+			}
+			if rule == nil {
+				continue
+			}
+
+			rec := Recording{
+				Airing:   a,
+				Rule:     rule.Name,
+				Priority: rule.Priority,
+				Start:    a.Start().Add(-rule.PaddingBefore),
+				End:      a.End().Add(rule.PaddingAfter),
+				rule:     rule,
+				from:     from,
+				to:       to,
+			}
+
+			if id, err := ParseProgramID(a.ProgramID); err == nil && id.Kind == ProgramShow {
+				shows = append(shows, rec)
+				continue
+			}
+
+			if b, ok := best[a.ProgramID]; !ok || betterAiring(a, b.Airing) {
+				best[a.ProgramID] = rec
+			}
+		}
+	}
+
+	plan := append([]Recording{}, shows...)
+	for _, rec := range best {
+		plan = append(plan, rec)
+	}
+
+	sort.Sort(recordingsByStart(plan))
+
+	return plan, nil
+}
+
+type recordingsByStart []Recording
+
+func (r recordingsByStart) Len() int      { return len(r) }
+func (r recordingsByStart) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r recordingsByStart) Less(i, j int) bool {
+	if !r[i].Start.Equal(r[j].Start) {
+		return r[i].Start.Before(r[j].Start)
+	}
+	return r[i].StationID < r[j].StationID
+}
@@ -0,0 +1,107 @@
+package schedulesdirect
+
+import (
+	"testing"
+	"time"
+)
+
+func testRulesGuide(t *testing.T) *Guide {
+	s1, err := JsonToSchedules([]byte(`{"stationID":"10001","programs":[{"airDateTime":"2014-07-30T01:00:00Z","duration":3600,"programID":"EP000000010001","new":true},{"airDateTime":"2014-07-30T03:00:00Z","duration":3600,"programID":"EP000000010001"},{"airDateTime":"2014-07-30T04:00:00Z","duration":3600,"programID":"EP000000010000"},{"airDateTime":"2014-07-30T05:00:00Z","duration":1800,"programID":"SH000000030000"},{"airDateTime":"2014-07-30T23:00:00Z","duration":1800,"programID":"SH000000030000"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2, err := JsonToSchedules([]byte(`{"stationID":"10002","programs":[{"airDateTime":"2014-07-30T02:00:00Z","duration":3600,"programID":"EP000000010001","videoProperties":["hdtv"]},{"airDateTime":"2014-07-30T06:00:00Z","duration":7200,"programID":"MV000000020000"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewGuide([]schedule{s1, s2}, []program{
+		{ProgramID: "EP000000010001", Titles: map[string]string{"title120": "Show One"}},
+		{ProgramID: "EP000000010000", Titles: map[string]string{"title120": "Show One"}},
+		{ProgramID: "MV000000020000", Titles: map[string]string{"title120": "A Movie"}, Descriptions: map[string][]DescriptionT{"description100": {{"A story about dragons.", "en"}}}, Cast: []cast{{person: person{PersonId: "person1", Role: "Actor"}}}},
+		{ProgramID: "SH000000030000", Titles: map[string]string{"title120": "News"}, Genres: []string{"News"}},
+	})
+}
+
+func TestPlanRecordingsSeries(t *testing.T) {
+	g := testRulesGuide(t)
+
+	plan, err := PlanRecordings(g, []Rule{{Name: "series", SeriesID: "SH000000010000", PaddingAfter: 2 * time.Minute}}, testTime("2014-07-30T00:00:00Z"), testTime("2014-07-31T00:00:00Z"), []string{"EP000000010000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the HD airing is preferred, EP000000010000 was recorded
+	if len(plan) != 1 {
+		t.Fatalf("len(plan) != 1: %v", plan)
+	}
+	if plan[0].StationID != "10002" || plan[0].Rule != "series" || !plan[0].End.Equal(testTime("2014-07-30T03:02:00Z")) {
+		t.Fatalf("plan[0] doesn't match: %v", plan[0])
+	}
+
+	plan, err = PlanRecordings(g, []Rule{{Title: "^Show", NewOnly: true}}, testTime("2014-07-30T00:00:00Z"), testTime("2014-07-31T00:00:00Z"), []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan) != 1 || plan[0].StationID != "10001" || !plan[0].New {
+		t.Fatalf("plan doesn't match: %v", plan)
+	}
+}
+
+func TestPlanRecordingsCriteria(t *testing.T) {
+	g := testRulesGuide(t)
+
+	rules := []Rule{
+		{Name: "keyword", Keyword: "DRAGONS"},
+		{Name: "person", PersonID: "person1", Priority: 2},
+		{Name: "news", Categories: []GenreCategory{CategoryNews}, StationIDs: []string{"10001"}, TimeFrom: 22 * time.Hour, TimeTo: 6 * time.Hour},
+	}
+
+	plan, err := PlanRecordings(g, rules, testTime("2014-07-30T00:00:00Z"), testTime("2014-07-31T00:00:00Z"), []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(plan) != 3 {
+		t.Fatalf("len(plan) != 3: %v", plan)
+	}
+	if plan[0].ProgramID != "SH000000030000" || plan[1].ProgramID != "MV000000020000" || plan[2].ProgramID != "SH000000030000" {
+		t.Fatalf("plan doesn't match: %s %s %s", plan[0].ProgramID, plan[1].ProgramID, plan[2].ProgramID)
+	}
+	if plan[1].Rule != "person" {
+		t.Fatalf(`plan[1].Rule != "person": %s`, plan[1].Rule)
+	}
+
+	if _, err := PlanRecordings(g, []Rule{{Title: "("}}, testTime("2014-07-30T00:00:00Z"), testTime("2014-07-31T00:00:00Z"), []string{}); err == nil {
+		t.Fatal("an invalid title should fail")
+	}
+}
+
+func TestPlanRecordingsSeriesID(t *testing.T) {
+	g := testRulesGuide(t)
+	from, to := testTime("2014-07-30T00:00:00Z"), testTime("2014-07-31T00:00:00Z")
+
+	tests := []struct {
+		seriesID string
+		expect   int
+	}{
+		{"SH000000010000", 2},
+		{"SH00000001", 2},
+		{"SH0000000", 0},
+		{"SH", 0},
+	}
+
+	for _, test := range tests {
+		plan, err := PlanRecordings(g, []Rule{{Name: "series", SeriesID: test.seriesID}}, from, to, []string{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(plan) != test.expect {
+			t.Fatalf("%s: len(plan) != %d: %v", test.seriesID, test.expect, plan)
+		}
+	}
+
+	if _, err := PlanRecordings(g, []Rule{{Name: "everything", Priority: 1}}, from, to, []string{}); err == nil {
+		t.Fatal("err == nil")
+	}
+}
@@ -3,6 +3,8 @@ package schedulesdirect
 import (
 	"bufio"
 	"bytes"
+	"compress/flate"
+	"compress/zlib"
 	"crypto/sha1"
 	"encoding/hex"
 	"encoding/json"
@@ -14,7 +16,6 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
-	"strings"
 	"time"
 )
 
@@ -78,6 +79,14 @@ func NewClient() *sdclient {
 	}
 }
 
+// NewClientWithURL returns a client of another server with the same API, like
+// cmd/sd-proxy. baseURL has no trailing slash.
+func NewClientWithURL(baseURL string) *sdclient {
+	return &sdclient{
+		baseURL: baseURL,
+	}
+}
+
 func (c sdclient) GetToken(username, password string) (string, error) {
 	tokenReq := tokenRequest{username, hashPassword(password)}
 
@@ -231,11 +240,18 @@ type lineups struct {
 	ServerID string `json:"serverID"`
 }
 
-// country must be ISO-3166-1 alpha 3, see : https://en.wikipedia.org/wiki/ISO_3166-1_alpha-3
+// country can be ISO-3166-1 alpha 2, alpha 3 or an English name, see : https://en.wikipedia.org/wiki/ISO_3166-1_alpha-3
+// It's sent as alpha 3. Both country and postalcode are validated before sending the request.
 func (c sdclient) GetHeadends(token, country, postalcode string) (map[string]headend, error) {
-	// There's a bug with postal code containing a space
-	// https://github.com/SchedulesDirect/JSON-Service/issues/31
-	postalcode = strings.Replace(postalcode, " ", "", -1)
+	country, errCountry := NormalizeCountry(country)
+	if errCountry != nil {
+		return map[string]headend{}, errCountry
+	}
+
+	postalcode, errPostalCode := NormalizePostalCode(country, postalcode)
+	if errPostalCode != nil {
+		return map[string]headend{}, errPostalCode
+	}
 
 	u, err := url.Parse(c.baseURL + apiVersion + "/headends")
 	if err != nil {
@@ -365,33 +381,37 @@ func (c sdclient) DelLineup(token, uri string) (int, error) {
 	return addDelLineup(c, token, uri, "DELETE", opLineupDel)
 }
 
+type channelMapEntry struct {
+	Channel   string `json:"channel"`
+	StationId string `json:"stationID"`
+}
+
+type station struct {
+	Affiliate   string `json:"affiliate"`
+	Broadcaster struct {
+		City       string `json:"city"`
+		Country    string `json:"country"`
+		Postalcode string `json:"postalcode"`
+	} `json:"broadcaster"`
+	Callsign  string `json:"callsign"`
+	Language  string `json:"language"`
+	Name      string `json:"name"`
+	StationID string `json:"stationID"`
+	Logo      struct {
+		URL       string `json:"URL"`
+		Dimension string `json:"dimension"`
+		Md5       string `json:"md5"`
+	}
+}
+
 type channelMapping struct {
-	Map []struct {
-		Channel   string `json:"channel"`
-		StationId string `json:"stationID"`
-	} `json:"map"`
+	Map      []channelMapEntry `json:"map"`
 	Metadata struct {
 		Lineup    string    `json:"lineup"`
 		Modified  time.Time `json:"modified"`
 		Transport string    `json:"transport"`
 	} `json:"metadata"`
-	Stations []struct {
-		affiliate   string `json:"affiliate"`
-		broadcaster struct {
-			city       string `json:"city"`
-			country    string `json:"country"`
-			postalcode string `json:"postalcode"`
-		} `json:"broadcaster"`
-		Callsign  string `json:"callsign"`
-		Language  string `json:"language"`
-		Name      string `json:"name"`
-		StationID string `json:"stationID"`
-		Logo      struct {
-			URL       string `json:"URL"`
-			Dimension string `json:"dimension"`
-			Md5       string `json:"md5"`
-		}
-	} `json:"stations"`
+	Stations []station `json:"stations"`
 
 	// To catch errors
 	Code    int    `json:"code"`
@@ -538,10 +558,48 @@ type DescriptionT struct {
 	DescriptionLanguage string `json:"descriptionLanguage"`
 }
 
+type qualityRating struct {
+	Increment   string `json:"increment"`
+	MaxRating   string `json:"maxRating"`
+	MinRating   string `json:"minRating"`
+	Rating      string `json:"rating"`
+	RatingsBody string `json:"ratingsBody"`
+}
+
+type programMetadata struct {
+	Season        int `json:"season"`
+	Episode       int `json:"episode"`
+	TotalEpisodes int `json:"totalEpisodes"`
+	TotalSeasons  int `json:"totalSeasons"`
+	Part          int `json:"part"`
+	TotalParts    int `json:"totalParts"`
+}
+
+type eventTeam struct {
+	Name   string `json:"name"`
+	IsHome bool   `json:"isHome"`
+}
+
+type eventDetails struct {
+	SubType  string      `json:"subType"`
+	Venue    string      `json:"venue100"`
+	Teams    []eventTeam `json:"teams"`
+	GameDate string      `json:"gameDate"` // 2014-08-12
+}
+
+type programImage struct {
+	Dimension string `json:"dimension"`
+	Md5       string `json:"md5"`
+	Uri       string `json:"uri"`
+}
+
+type recommendation struct {
+	ProgramID string `json:"programID"`
+	Title120  string `json:"title120"`
+}
+
 type program struct {
-	EventDetails struct {
-		SubType string `json:"subType"`
-	} `json:"eventDetails"`
+	EventDetails eventDetails `json:"eventDetails"`
 
 	Genres          []string                  `json:"genres"`
 	Md5             string                    `json:"md5"`
@@ -552,28 +610,18 @@ type program struct {
 	Descriptions    map[string][]DescriptionT `json:"descriptions"`
 	Cast            []cast                    `json:"cast"`
 	Crew            []person                  `json:"crew"`
-	Recommendations []struct {
-		ProgramID string `json:"programID"`
-		Title120  string `json:"title120"`
-	} `json:"recommendations"`
-	Images []struct {
-		Dimension string `json:"dimension"`
-		Md5       string `json:"md5"`
-		Uri       string `json:"uri"`
-	}
+	Recommendations []recommendation          `json:"recommendations"`
+	Images          []programImage
 
 	Movie struct {
-		Duration      int    `json:"duration"`
-		Year          string `json:"year"`
-		QualityRating []struct {
-			Increment   string `json:"increment"`
-			MaxRating   string `json:"maxRating"`
-			MinRating   string `json:"minRating"`
-			Rating      string `json:"rating"`
-			RatingsBody string `json:"ratingsBody"`
-		} `json:"qualityRating"`
+		Duration      int             `json:"duration"`
+		Year          string          `json:"year"`
+		QualityRating []qualityRating `json:"qualityRating"`
 	} `json:"movie"`
 
+	// one provider (Gracenote, TVmaze...) per entry
+	Metadata []map[string]programMetadata `json:"metadata"`
+
 	// for errors
 	Code    int    `json:"code"`
 	Message string `json:"message"`
@@ -616,7 +664,13 @@ func (c sdclient) GetProgramsInfo(token string, programs []string) ([]program, e
 
 	var result []program
 
-	scanner := bufio.NewScanner(resp.Body)
+	body, errBody := decodedBody(resp)
+	if errBody != nil {
+		return []program{}, errBody
+	}
+	defer body.Close()
+
+	scanner := bufio.NewScanner(body)
 	for scanner.Scan() {
 		var p program
 
@@ -648,6 +702,26 @@ func (c sdclient) GetProgramsInfo(token string, programs []string) ([]program, e
 	}
 }
 
+// isZlib tells if a body starts with a zlib header.
+func isZlib(header []byte) bool {
+	return len(header) >= 2 && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0
+}
+
+// decodedBody returns the body of a response to a request accepting deflate.
+// "deflate" should be zlib but some servers send raw deflate.
+func decodedBody(resp *http.Response) (io.ReadCloser, error) {
+	if resp.Header.Get("Content-Encoding") != "deflate" {
+		return resp.Body, nil
+	}
+
+	reader := bufio.NewReader(resp.Body)
+
+	if header, _ := reader.Peek(2); isZlib(header) {
+		return zlib.NewReader(reader)
+	}
+	return flate.NewReader(reader), nil
+}
+
 type schedule struct {
 	StationID string `json:"stationID"`
 	Metadata  struct {
@@ -655,24 +729,28 @@ type schedule struct {
 		EndDate   string `json:"endDate"` // 2014-08-12
 		StartDate string `json:"startDate"`
 	} `json:"metadata"`
-	Programs []struct {
-		AirDateTime     time.Time `json:"airDateTime"` // full iso datetime
-		AudioProperties []string  `json:"audioProperties"`
-		VideoProperties []string  `json:"videoProperties"`
-		ContentRating   []struct {
-			Body string `json:"body"`
-			Code string `json:"code"`
-		}
-		ContentAdvisory map[string][]string
-		Duration        int    `json:"duration"`
-		Md5             string `json:"md5"`
-		ProgramID       string `json:"programID"`
-		Syndication     struct {
-			Source string `json:"source"`
-			Type   string `json:"type"`
-		} `json:"syndication"`
-		New bool `json:"new"`
-	} `json:"programs"`
+	Programs []scheduleProgram `json:"programs"`
+}
+
+type contentRating struct {
+	Body string `json:"body"`
+	Code string `json:"code"`
+}
+
+type scheduleProgram struct {
+	AirDateTime     time.Time  `json:"airDateTime"` // full iso datetime
+	AudioProperties Properties `json:"audioProperties"`
+	VideoProperties Properties `json:"videoProperties"`
+	ContentRating   []contentRating
+	ContentAdvisory map[string][]string
+	Duration        int    `json:"duration"`
+	Md5             string `json:"md5"`
+	ProgramID       string `json:"programID"`
+	Syndication     struct {
+		Source string `json:"source"`
+		Type   string `json:"type"`
+	} `json:"syndication"`
+	New bool `json:"new"`
 }
 
 func (c sdclient) GetSchedules(token string, stationsIDs []string) ([]schedule, error) {
@@ -708,7 +786,13 @@ func (c sdclient) GetSchedules(token string, stationsIDs []string) ([]schedule,
 
 	var result []schedule
 
-	reader := bufio.NewReader(resp.Body)
+	body, errBody := decodedBody(resp)
+	if errBody != nil {
+		return []schedule{}, errBody
+	}
+	defer body.Close()
+
+	reader := bufio.NewReader(body)
 
 	var buf2 bytes.Buffer
 
@@ -2,8 +2,11 @@ package schedulesdirect
 
 import (
 	"bytes"
+	"compress/flate"
+	"compress/zlib"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
@@ -639,3 +642,70 @@ func TestGetSchedulesFailsStationNotInLineup(t *testing.T) {
 		t.Fail()
 	}
 }
+
+// deflateWriter encodes like the servers do, zlib or raw deflate.
+func deflateWriter(w io.Writer, raw bool) io.WriteCloser {
+	if raw {
+		fw, _ := flate.NewWriter(w, flate.DefaultCompression)
+		return fw
+	}
+	return zlib.NewWriter(w)
+}
+
+func TestGetProgramsInfoDeflate(t *testing.T) {
+	for _, raw := range []bool{false, true} {
+		setup()
+
+		mux.HandleFunc("/20131021/programs",
+			func(w http.ResponseWriter, r *http.Request) {
+				testHeader(t, r, "Accept-Encoding", "deflate")
+
+				w.Header().Set("Content-Encoding", "deflate")
+				dw := deflateWriter(w, raw)
+				fmt.Fprint(dw, `{"programID":"program1","titles":{"title120":"title1"}}
+{"programID":"program2","titles":{"title120":"title2"}}`)
+				dw.Close()
+			},
+		)
+
+		programs, err := client.GetProgramsInfo("token1", []string{"program1", "program2"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(programs) != 2 || programs[1].Titles["title120"] != "title2" {
+			t.Fatalf("raw %v: programs doesn't match: %v", raw, programs)
+		}
+
+		server.Close()
+	}
+}
+
+func TestGetSchedulesDeflate(t *testing.T) {
+	for _, raw := range []bool{false, true} {
+		setup()
+
+		mux.HandleFunc("/20131021/schedules",
+			func(w http.ResponseWriter, r *http.Request) {
+				testHeader(t, r, "Accept-Encoding", "deflate")
+
+				w.Header().Set("Content-Encoding", "deflate")
+				dw := deflateWriter(w, raw)
+				fmt.Fprint(dw, `{"programs":[{"airDateTime":"2014-07-30T00:30:00Z","duration":1800,"programID":"program1"}],"stationID":"10001"}
+{"programs":[{"airDateTime":"2014-07-30T00:30:00Z","duration":1800,"programID":"program3"}],"stationID":"10002"}`)
+				dw.Close()
+			},
+		)
+
+		schedules, err := client.GetSchedules("token1", []string{"10001", "10002"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(schedules) != 2 || schedules[1].StationID != "10002" {
+			t.Fatalf("raw %v: schedules doesn't match: %v", raw, schedules)
+		}
+
+		server.Close()
+	}
+}
@@ -0,0 +1,129 @@
+package schedulesdirect
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+)
+
+// the service accepts up to 5000 programs per request
+const maxProgramsPerRequest = 5000
+
+// Snapshot is the guide data of an account: the channel mappings of its
+// lineups with their schedules and programs. It can be saved to a local store
+// with Save and loaded back with LoadSnapshot.
+type Snapshot struct {
+	Time            time.Time        `json:"time"`
+	ChannelMappings []channelMapping `json:"channelMappings"`
+	Schedules       []schedule       `json:"schedules"`
+	Programs        []program        `json:"programs"`
+}
+
+// Sync downloads the channel mappings of the account's lineups, the schedules
+// of their stations and the programs of the schedules.
+func (c sdclient) Sync(token string) (Snapshot, error) {
+	s := Snapshot{
+		Time:            time.Now(),
+		ChannelMappings: []channelMapping{},
+		Schedules:       []schedule{},
+		Programs:        []program{},
+	}
+
+	l, errLineups := c.GetLineups(token)
+	if errLineups != nil {
+		return Snapshot{}, errLineups
+	}
+
+	for _, lineup := range l.Lineups {
+		cm, err := c.GetChannelMapping(token, lineup.Uri)
+		if err != nil {
+			return Snapshot{}, err
+		}
+		s.ChannelMappings = append(s.ChannelMappings, cm)
+	}
+
+	stationIDs := MergeChannelMappings(s.ChannelMappings...).StationIDs()
+	if len(stationIDs) == 0 {
+		return s, nil
+	}
+
+	schedules, errSchedules := c.GetSchedules(token, stationIDs)
+	if errSchedules != nil {
+		return Snapshot{}, errSchedules
+	}
+	s.Schedules = schedules
+
+	seen := make(map[string]bool)
+	var programIDs []string
+	for _, sc := range schedules {
+		for _, p := range sc.Programs {
+			if !seen[p.ProgramID] {
+				seen[p.ProgramID] = true
+				programIDs = append(programIDs, p.ProgramID)
+			}
+		}
+	}
+
+	sort.Strings(programIDs)
+
+	for len(programIDs) > 0 {
+		n := len(programIDs)
+		if n > maxProgramsPerRequest {
+			n = maxProgramsPerRequest
+		}
+
+		programs, err := c.GetProgramsInfo(token, programIDs[:n])
+		if err != nil {
+			return Snapshot{}, err
+		}
+		s.Programs = append(s.Programs, programs...)
+
+		programIDs = programIDs[n:]
+	}
+
+	return s, nil
+}
+
+func LoadSnapshot(r io.Reader) (Snapshot, error) {
+	var s Snapshot
+
+	errDecode := json.NewDecoder(r).Decode(&s)
+	if errDecode != nil {
+		return Snapshot{}, errDecode
+	}
+
+	return s, nil
+}
+
+func (s Snapshot) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(s)
+}
+
+func (s Snapshot) Guide() *Guide {
+	return NewGuide(s.Schedules, s.Programs)
+}
+
+func (s Snapshot) Stations() *StationIndex {
+	return MergeChannelMappings(s.ChannelMappings...)
+}
+
+// Lineups returns a Lineup for each channel mapping, in order.
+func (s Snapshot) Lineups() []*Lineup {
+	lineups := make([]*Lineup, len(s.ChannelMappings))
+	for i, cm := range s.ChannelMappings {
+		lineups[i] = NewLineup(cm)
+	}
+	return lineups
+}
+
+// Grid builds the grid of a lineup, false when the snapshot doesn't have it.
+func (s Snapshot) Grid(lineup string, opts GridOptions) (Grid, bool, error) {
+	for _, cm := range s.ChannelMappings {
+		if cm.Metadata.Lineup == lineup {
+			g, err := BuildGrid(cm, s.Schedules, s.Programs, opts)
+			return g, true, err
+		}
+	}
+	return Grid{}, false, nil
+}
@@ -0,0 +1,94 @@
+package schedulesdirect
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestSync(t *testing.T) {
+	setup()
+	defer server.Close()
+
+	mux.HandleFunc(apiVersion+"/lineups",
+		func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "GET")
+			testHeader(t, r, "token", "token1")
+
+			fmt.Fprint(w, `{"serverID":"serverid1","datetime":"2014-07-30T02:34:37Z","lineups":[{"name":"name1","type":"type1","location":"location1","uri":"/20131021/lineups/CAN-0000001-X"}]}`)
+		},
+	)
+
+	mux.HandleFunc("/20131021/lineups/CAN-0000001-X",
+		func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "GET")
+			testHeader(t, r, "token", "token1")
+
+			fmt.Fprint(w, `{"map":[{"channel":"2","stationID":"10001"},{"channel":"3","stationID":"10002"}],"metadata":{"lineup":"CAN-0000001-X","transport":"Cable"},"stations":[{"callsign":"callsign1","stationID":"10001"},{"callsign":"callsign2","stationID":"10002"}]}`)
+		},
+	)
+
+	mux.HandleFunc("/20131021/schedules",
+		func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "POST")
+			testPayload(t, r, []byte(`{"request":["10001","10002"]}`+"\n"))
+
+			fmt.Fprint(w, `{"programs":[{"airDateTime":"2014-07-30T00:30:00Z","duration":1800,"programID":"EP000000010002"},{"airDateTime":"2014-07-30T01:00:00Z","duration":1800,"programID":"EP000000010001"}],"stationID":"10001"}
+{"programs":[{"airDateTime":"2014-07-30T00:30:00Z","duration":1800,"programID":"EP000000010001"}],"stationID":"10002"}`)
+		},
+	)
+
+	mux.HandleFunc("/20131021/programs",
+		func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, "POST")
+			testPayload(t, r, []byte(`{"request":["EP000000010001","EP000000010002"]}`+"\n"))
+
+			fmt.Fprint(w, `{"programID":"EP000000010001","titles":{"title120":"title1"}}
+{"programID":"EP000000010002","titles":{"title120":"title2"}}`)
+		},
+	)
+
+	s, err := client.Sync("token1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(s.ChannelMappings) != 1 || len(s.Schedules) != 2 || len(s.Programs) != 2 {
+		t.Fatalf("s doesn't match: %v", s)
+	}
+
+	var buf bytes.Buffer
+	if err := s.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadSnapshot(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a, ok := loaded.Guide().Now("10002"); ok {
+		t.Fatalf("a != nil: %v", a)
+	}
+	if airings := loaded.Guide().Airings("10001"); len(airings) != 2 || airings[1].Program == nil || airings[1].Program.Titles["title120"] != "title1" {
+		t.Fatalf("airings doesn't match: %v", airings)
+	}
+
+	lineups := loaded.Lineups()
+	if len(lineups) != 1 || len(lineups[0].Stations) != 2 {
+		t.Fatalf("lineups doesn't match: %v", lineups)
+	}
+
+	if _, ok, _ := loaded.Grid("USA-0000001-X", GridOptions{Start: testTime("2014-07-30T00:30:00Z"), Slots: 2}); ok {
+		t.Fatal("ok == true")
+	}
+
+	grid, ok, err := loaded.Grid("CAN-0000001-X", GridOptions{Start: testTime("2014-07-30T00:30:00Z"), Slots: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || len(grid.Rows) != 2 {
+		t.Fatalf("grid doesn't match: %v", grid)
+	}
+}
@@ -0,0 +1,147 @@
+package schedulesdirect
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// HomeTeam returns the team flagged as home.
+func (e eventDetails) HomeTeam() (string, bool) {
+	for _, t := range e.Teams {
+		if t.IsHome {
+			return t.Name, true
+		}
+	}
+	return "", false
+}
+
+// GameDay parses GameDate, the time is at midnight UTC.
+func (e eventDetails) GameDay() (time.Time, error) {
+	return time.Parse("2006-01-02", e.GameDate)
+}
+
+type Broadcast int
+
+const (
+	BroadcastUnknown Broadcast = iota
+	BroadcastLive
+	BroadcastTapeDelayed
+	BroadcastNew
+	BroadcastRepeat
+)
+
+var broadcastNames = map[Broadcast]string{
+	BroadcastUnknown:     "Unknown",
+	BroadcastLive:        "Live",
+	BroadcastTapeDelayed: "Tape delayed",
+	BroadcastNew:         "New",
+	BroadcastRepeat:      "Repeat",
+}
+
+func (b Broadcast) String() string {
+	return broadcastNames[b]
+}
+
+// Broadcast tells if the airing is live, tape delayed, new or a repeat.
+func (sp scheduleProgram) Broadcast() Broadcast {
+	switch {
+	case sp.IsLive():
+		return BroadcastLive
+	case sp.IsTapeDelayed():
+		return BroadcastTapeDelayed
+	case sp.New:
+		return BroadcastNew
+	default:
+		return BroadcastRepeat
+	}
+}
+
+// SportsGame is an airing of a sports event, League is the title of the
+// program (like "MLB Baseball").
+type SportsGame struct {
+	*Airing
+	League    string
+	Teams     []string
+	HomeTeam  string
+	Venue     string
+	Broadcast Broadcast
+}
+
+// SportsIndex finds the games of a schedule window by team or league.
+type SportsIndex struct {
+	// sorted by start
+	Games []SportsGame
+}
+
+// NewSportsIndex indexes the airings of the stations overlapping [from, to)
+// in the Sports category or with teams.
+func NewSportsIndex(g *Guide, stationIDs []string, from, to time.Time) *SportsIndex {
+	si := &SportsIndex{Games: []SportsGame{}}
+
+	for _, id := range stationIDs {
+		for _, a := range g.Between(id, from, to) {
+			p := a.Program
+			if p == nil || (len(p.EventDetails.Teams) == 0 && !p.HasCategory(CategorySports)) {
+				continue
+			}
+
+			game := SportsGame{
+				Airing:    a,
+				League:    p.Titles["title120"],
+				Teams:     []string{},
+				Venue:     p.EventDetails.Venue,
+				Broadcast: a.Broadcast(),
+			}
+			game.HomeTeam, _ = p.EventDetails.HomeTeam()
+			for _, t := range p.EventDetails.Teams {
+				game.Teams = append(game.Teams, t.Name)
+			}
+
+			si.Games = append(si.Games, game)
+		}
+	}
+
+	sort.Stable(sportsGames(si.Games))
+
+	return si
+}
+
+// ByTeam returns the games of a team, "red sox" matches "Boston Red Sox".
+func (si *SportsIndex) ByTeam(team string) []SportsGame {
+	team = strings.ToLower(team)
+
+	result := []SportsGame{}
+	for _, game := range si.Games {
+		for _, t := range game.Teams {
+			if strings.Contains(strings.ToLower(t), team) {
+				result = append(result, game)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// ByLeague returns the games whose title or genres contain league.
+func (si *SportsIndex) ByLeague(league string) []SportsGame {
+	league = strings.ToLower(league)
+
+	result := []SportsGame{}
+	for _, game := range si.Games {
+		found := strings.Contains(strings.ToLower(game.League), league)
+		for _, genre := range game.Program.Genres {
+			found = found || strings.Contains(strings.ToLower(genre), league)
+		}
+		if found {
+			result = append(result, game)
+		}
+	}
+	return result
+}
+
+type sportsGames []SportsGame
+
+func (s sportsGames) Len() int           { return len(s) }
+func (s sportsGames) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s sportsGames) Less(i, j int) bool { return s[i].AirDateTime.Before(s[j].AirDateTime) }
@@ -0,0 +1,57 @@
+package schedulesdirect
+
+import (
+	"testing"
+)
+
+func TestSportsIndex(t *testing.T) {
+	s, err := JsonToSchedules([]byte(`{"stationID":"10001","programs":[{"airDateTime":"2014-07-30T23:00:00Z","duration":10800,"programID":"SP000000010002","videoProperties":["hdtv","live"]},{"airDateTime":"2014-07-30T18:00:00Z","duration":10800,"programID":"SP000000010001","syndication":{"source":"ss1","type":"Tape"}},{"airDateTime":"2014-07-30T17:00:00Z","duration":3600,"programID":"EP000000020001","new":true}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p1, err := JsonToProgram([]byte(`{"programID":"SP000000010001","titles":{"title120":"MLB Baseball"},"genres":["Baseball"],"eventDetails":{"subType":"Sports event","venue100":"Fenway Park","gameDate":"2014-07-30","teams":[{"name":"New York Yankees"},{"name":"Boston Red Sox","isHome":true}]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p2, err := JsonToProgram([]byte(`{"programID":"SP000000010002","titles":{"title120":"NHL Hockey"},"genres":["Hockey"],"eventDetails":{"subType":"Sports event","teams":[{"name":"Montreal Canadiens","isHome":true},{"name":"Boston Bruins"}]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	home, ok := p1.EventDetails.HomeTeam()
+	if !ok || home != "Boston Red Sox" {
+		t.Fatalf(`home != "Boston Red Sox": %s`, home)
+	}
+	if day, err := p1.EventDetails.GameDay(); err != nil || day.Day() != 30 {
+		t.Fatalf("day doesn't match: %s %v", day, err)
+	}
+
+	g := NewGuide([]schedule{s}, []program{p1, p2, {ProgramID: "EP000000020001", Genres: []string{"Sitcom"}}})
+
+	si := NewSportsIndex(g, []string{"10001"}, testTime("2014-07-30T00:00:00Z"), testTime("2014-07-31T00:00:00Z"))
+	if len(si.Games) != 2 {
+		t.Fatalf("len(si.Games) != 2: %d", len(si.Games))
+	}
+
+	games := si.ByTeam("boston")
+	if len(games) != 2 || games[0].ProgramID != "SP000000010001" {
+		t.Fatalf("games don't match: %v", games)
+	}
+	if games[0].Venue != "Fenway Park" || games[0].Broadcast != BroadcastTapeDelayed {
+		t.Fatalf("games[0] doesn't match: %v", games[0])
+	}
+	if games[1].Broadcast != BroadcastLive || games[1].HomeTeam != "Montreal Canadiens" {
+		t.Fatalf("games[1] doesn't match: %v", games[1])
+	}
+
+	games = si.ByLeague("nhl")
+	if len(games) != 1 || games[0].ProgramID != "SP000000010002" {
+		t.Fatalf("games don't match: %v", games)
+	}
+
+	if b := g.Airings("10001")[0].Broadcast(); b != BroadcastNew {
+		t.Fatalf("b != BroadcastNew: %s", b)
+	}
+}
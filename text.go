@@ -0,0 +1,144 @@
+package schedulesdirect
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// TextVariant is a title or a description with its maximum length taken from
+// its key ("title120", "description1000"), 0 when the key has none.
+type TextVariant struct {
+	Key      string
+	Text     string
+	Language string
+	Length   int
+}
+
+func keyLength(key string) int {
+	i := len(key)
+	for i > 0 && key[i-1] >= '0' && key[i-1] <= '9' {
+		i--
+	}
+	n, _ := strconv.Atoi(key[i:])
+	return n
+}
+
+// TitleVariants returns the titles sorted by length, the titles don't have a language.
+func (p program) TitleVariants() []TextVariant {
+	variants := []TextVariant{}
+	for key, title := range p.Titles {
+		variants = append(variants, TextVariant{key, title, "", keyLength(key)})
+	}
+
+	sort.Sort(textVariants(variants))
+
+	return variants
+}
+
+// DescriptionVariants returns all the descriptions sorted by language and length.
+func (p program) DescriptionVariants() []TextVariant {
+	variants := []TextVariant{}
+	for key, descriptions := range p.Descriptions {
+		for _, d := range descriptions {
+			variants = append(variants, TextVariant{key, d.Description, d.DescriptionLanguage, keyLength(key)})
+		}
+	}
+
+	sort.Sort(textVariants(variants))
+
+	return variants
+}
+
+// TextPreference selects titles and descriptions. Languages are in order of
+// preference, "fr" matches "fr-CA". MaxLength is the maximum number of
+// characters of the text, ignored when <= 0.
+type TextPreference struct {
+	Languages []string
+	MaxLength int
+}
+
+func languageMatches(want, have string) bool {
+	want, have = strings.ToLower(want), strings.ToLower(have)
+	return want == have || strings.HasPrefix(have, want+"-") || strings.HasPrefix(want, have+"-")
+}
+
+// longer compares the texts in characters and then the lengths of the keys.
+func longer(a, b TextVariant) bool {
+	if na, nb := utf8.RuneCountInString(a.Text), utf8.RuneCountInString(b.Text); na != nb {
+		return na > nb
+	}
+	return a.Length > b.Length
+}
+
+// best returns the longest variant fitting MaxLength or else the shortest one
+// cut to MaxLength with an ellipsis.
+func (tp TextPreference) best(variants []TextVariant) (TextVariant, bool) {
+	if len(variants) == 0 {
+		return TextVariant{}, false
+	}
+
+	var longest, shortest TextVariant
+	fits := false
+
+	for i, v := range variants {
+		if tp.MaxLength <= 0 || utf8.RuneCountInString(v.Text) <= tp.MaxLength {
+			if !fits || longer(v, longest) {
+				longest = v
+			}
+			fits = true
+		}
+		if i == 0 || longer(shortest, v) {
+			shortest = v
+		}
+	}
+
+	if fits {
+		return longest, true
+	}
+
+	runes := []rune(shortest.Text)
+	shortest.Text = string(runes[:tp.MaxLength-1]) + "…"
+	return shortest, true
+}
+
+func (tp TextPreference) Title(p program) (TextVariant, bool) {
+	return tp.best(p.TitleVariants())
+}
+
+// Description falls back to English and then to any language when none of
+// the Languages is available.
+func (tp TextPreference) Description(p program) (TextVariant, bool) {
+	variants := p.DescriptionVariants()
+
+	languages := append(append([]string{}, tp.Languages...), "en")
+	for _, l := range languages {
+		var matching []TextVariant
+		for _, v := range variants {
+			if languageMatches(l, v.Language) {
+				matching = append(matching, v)
+			}
+		}
+
+		if v, ok := tp.best(matching); ok {
+			return v, true
+		}
+	}
+
+	return tp.best(variants)
+}
+
+type textVariants []TextVariant
+
+func (t textVariants) Len() int      { return len(t) }
+func (t textVariants) Swap(i, j int) { t[i], t[j] = t[j], t[i] }
+func (t textVariants) Less(i, j int) bool {
+	if t[i].Language != t[j].Language {
+		return t[i].Language < t[j].Language
+	}
+	if t[i].Length != t[j].Length {
+		return t[i].Length < t[j].Length
+	}
+	return t[i].Text < t[j].Text
+}
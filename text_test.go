@@ -0,0 +1,96 @@
+package schedulesdirect
+
+import (
+	"testing"
+)
+
+func testTextProgram(t *testing.T) program {
+	p, err := JsonToProgram([]byte(`{"programID":"EP012345670001","titles":{"title120":"A long title","title40":"Title"},"descriptions":{"description100":[{"description":"Short en","descriptionLanguage":"en"},{"description":"Court fr","descriptionLanguage":"fr"}],"description1000":[{"description":"Long description en","descriptionLanguage":"en"},{"description":"Longue description fr","descriptionLanguage":"fr-CA"}]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestTextPreferenceTitle(t *testing.T) {
+	p := testTextProgram(t)
+
+	if v, _ := (TextPreference{}).Title(p); v.Text != "A long title" {
+		t.Fatalf(`v.Text != "A long title": %s`, v.Text)
+	}
+	if v, _ := (TextPreference{MaxLength: 60}).Title(p); v.Text != "A long title" {
+		t.Fatalf(`v.Text != "A long title": %s`, v.Text)
+	}
+	if v, _ := (TextPreference{MaxLength: 10}).Title(p); v.Text != "Title" {
+		t.Fatalf(`v.Text != "Title": %s`, v.Text)
+	}
+
+	// nothing fits, the shortest is cut
+	if v, _ := (TextPreference{MaxLength: 4}).Title(p); v.Text != "Tit…" || v.Key != "title40" {
+		t.Fatalf(`v doesn't match: %v`, v)
+	}
+
+	// the same length in characters, the key is the tie-breaker
+	p, err := JsonToProgram([]byte(`{"programID":"EP012345670001","titles":{"title120":"Épisode","title40":"Episode"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := (TextPreference{MaxLength: 7}).Title(p); v.Text != "Épisode" {
+		t.Fatalf(`v.Text != "Épisode": %s`, v.Text)
+	}
+}
+
+func TestTextPreferenceDescription(t *testing.T) {
+	p := testTextProgram(t)
+
+	tests := []struct {
+		tp     TextPreference
+		expect string
+	}{
+		{TextPreference{Languages: []string{"fr", "en"}}, "Longue description fr"},
+		{TextPreference{Languages: []string{"fr-CA"}, MaxLength: 10}, "Court fr"},
+		{TextPreference{Languages: []string{"es"}, MaxLength: 10}, "Short en"},
+		{TextPreference{}, "Long description en"},
+	}
+
+	for _, test := range tests {
+		v, ok := test.tp.Description(p)
+		if !ok {
+			t.Fatalf("%v: no description", test.tp)
+		} else if v.Text != test.expect {
+			t.Fatalf("%v: v.Text (%s) != %s", test.tp, v.Text, test.expect)
+		}
+	}
+
+	if len(p.DescriptionVariants()) != 4 {
+		t.Fatalf("len(p.DescriptionVariants()) != 4: %d", len(p.DescriptionVariants()))
+	}
+
+	if _, ok := (TextPreference{}).Description(program{}); ok {
+		t.Fatal("an empty program shouldn't have a description")
+	}
+}
+
+func TestTextPreferenceDescriptionLength(t *testing.T) {
+	p, err := JsonToProgram([]byte(`{"programID":"EP000000010001","descriptions":{"description1000":[{"descriptionLanguage":"fr","description":"Une longue description"},{"descriptionLanguage":"de","description":"Eine lange Beschreibung"}],"description100":[{"descriptionLanguage":"fr-CA","description":"Court"},{"descriptionLanguage":"es","description":"Corto es"}]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the longest of the matching languages, not the last one sorted
+	v, _ := TextPreference{Languages: []string{"fr"}}.Description(p)
+	if v.Text != "Une longue description" {
+		t.Fatalf(`v.Text != "Une longue description": %s`, v.Text)
+	}
+
+	// any language, the longest fitting
+	v, _ = TextPreference{Languages: []string{"it"}, MaxLength: 10}.Description(p)
+	if v.Text != "Corto es" {
+		t.Fatalf(`v.Text != "Corto es": %s`, v.Text)
+	}
+
+	v, _ = TextPreference{Languages: []string{"it"}}.Description(p)
+	if v.Text != "Eine lange Beschreibung" {
+		t.Fatalf(`v.Text != "Eine lange Beschreibung": %s`, v.Text)
+	}
+}
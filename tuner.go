@@ -0,0 +1,231 @@
+package schedulesdirect
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TunerRecording is a recording assigned to a tuner of a transport. Alternate
+// is true when another airing of the programID replaced the planned one.
+type TunerRecording struct {
+	Recording
+	Transport string
+	Tuner     int
+	Alternate bool
+}
+
+// TunerConflict is a recording that couldn't be assigned, With are the
+// recordings using the tuners at that time.
+type TunerConflict struct {
+	Recording Recording
+	Reason    string
+	With      []TunerRecording
+}
+
+type TunerPlan struct {
+	Recordings []TunerRecording
+	Conflicts  []TunerConflict
+}
+
+type tunerState struct {
+	// recordings by transport and tuner
+	tuners map[string][][]TunerRecording
+}
+
+func (ts *tunerState) free(transport string, tuner int, rec Recording) bool {
+	for _, r := range ts.tuners[transport][tuner] {
+		if r.Start.Before(rec.End) && r.End.After(rec.Start) {
+			return false
+		}
+	}
+	return true
+}
+
+func (ts *tunerState) busy(transport string, rec Recording) []TunerRecording {
+	var busy []TunerRecording
+	for _, tuner := range ts.tuners[transport] {
+		for _, r := range tuner {
+			if r.Start.Before(rec.End) && r.End.After(rec.Start) {
+				busy = append(busy, r)
+			}
+		}
+	}
+	return busy
+}
+
+// place assigns rec to the first free tuner of a transport of its station.
+func (ts *tunerState) place(si *StationIndex, rec Recording, alternate bool) (TunerRecording, bool) {
+	for _, transport := range stationTransports(si, rec.StationID) {
+		for i := range ts.tuners[transport] {
+			if ts.free(transport, i, rec) {
+				tr := TunerRecording{rec, transport, i, alternate}
+				ts.tuners[transport][i] = append(ts.tuners[transport][i], tr)
+				return tr, true
+			}
+		}
+	}
+	return TunerRecording{}, false
+}
+
+func stationTransports(si *StationIndex, stationID string) []string {
+	var transports []string
+	for _, sc := range si.Channels(stationID) {
+		if !containsString(transports, sc.Transport) {
+			transports = append(transports, sc.Transport)
+		}
+	}
+	return transports
+}
+
+// ResolveConflicts assigns the recordings of a plan to the tuners of each
+// transport (like "Cable" or "Antenna", see channelMapping.Metadata.Transport)
+// of the stations in si. Recordings are assigned by Priority and then start;
+// when no tuner is free, the other airings of the same programID in the
+// window of the plan and matching the same rule are tried. The recordings that
+// can't be assigned are reported as conflicts.
+func ResolveConflicts(g *Guide, si *StationIndex, plan []Recording, tuners map[string]int) TunerPlan {
+	ts := &tunerState{tuners: make(map[string][][]TunerRecording)}
+	for transport, n := range tuners {
+		if n > 0 {
+			ts.tuners[transport] = make([][]TunerRecording, n)
+		}
+	}
+
+	// alternate airings by programID
+	airings := make(map[string][]*Airing)
+	for _, stationID := range g.StationIDs() {
+		for _, a := range g.Airings(stationID) {
+			airings[a.ProgramID] = append(airings[a.ProgramID], a)
+		}
+	}
+
+	planned := make(map[*Airing]bool, len(plan))
+	for _, rec := range plan {
+		planned[rec.Airing] = true
+	}
+
+	ordered := append([]Recording{}, plan...)
+	sort.Sort(recordingsByPriority(ordered))
+
+	result := TunerPlan{Recordings: []TunerRecording{}, Conflicts: []TunerConflict{}}
+
+	for _, rec := range ordered {
+		if tr, ok := ts.place(si, rec, false); ok {
+			result.Recordings = append(result.Recordings, tr)
+			continue
+		}
+
+		alternates := rec.alternates(airings[rec.ProgramID])
+		sort.Sort(airingsByPreference(alternates))
+
+		placed := false
+		for _, a := range alternates {
+			if planned[a] {
+				continue
+			}
+
+			alt := Recording{
+				Airing:   a,
+				Rule:     rec.Rule,
+				Priority: rec.Priority,
+				Start:    a.Start().Add(rec.Start.Sub(rec.Airing.Start())),
+				End:      a.End().Add(rec.End.Sub(rec.Airing.End())),
+			}
+
+			if tr, ok := ts.place(si, alt, true); ok {
+				result.Recordings = append(result.Recordings, tr)
+				planned[a] = true
+				placed = true
+				break
+			}
+		}
+
+		if !placed {
+			result.Conflicts = append(result.Conflicts, conflict(ts, si, tuners, rec))
+		}
+	}
+
+	sort.Sort(tunerRecordingsByStart(result.Recordings))
+
+	return result
+}
+
+// alternates returns the airings which can replace the recording's one: in the
+// window of the plan and matching the same rule, earlier ones included since
+// PlanRecordings prefers HD to earlier airings. Recordings not made by
+// PlanRecordings have none.
+func (rec Recording) alternates(airings []*Airing) []*Airing {
+	if rec.rule == nil {
+		return nil
+	}
+
+	// the airings of a SH programID aren't the same content
+	if id, err := ParseProgramID(rec.ProgramID); err == nil && id.Kind == ProgramShow {
+		return nil
+	}
+
+	var alternates []*Airing
+	for _, a := range airings {
+		if a != rec.Airing && a.overlaps(rec.from, rec.to) && rec.rule.match(a) {
+			alternates = append(alternates, a)
+		}
+	}
+	return alternates
+}
+
+func conflict(ts *tunerState, si *StationIndex, tuners map[string]int, rec Recording) TunerConflict {
+	c := TunerConflict{Recording: rec, With: []TunerRecording{}}
+
+	var reasons []string
+	for _, transport := range stationTransports(si, rec.StationID) {
+		if tuners[transport] <= 0 {
+			continue
+		}
+
+		busy := ts.busy(transport, rec)
+		c.With = append(c.With, busy...)
+
+		var ids []string
+		for _, b := range busy {
+			ids = append(ids, b.ProgramID)
+		}
+		reasons = append(reasons, fmt.Sprintf("all %d %s tuner(s) busy with %s", tuners[transport], transport, strings.Join(ids, ", ")))
+	}
+
+	if len(reasons) == 0 {
+		c.Reason = fmt.Sprintf("no tuner for station %s", rec.StationID)
+	} else {
+		c.Reason = strings.Join(reasons, "; ")
+	}
+
+	return c
+}
+
+type recordingsByPriority []Recording
+
+func (r recordingsByPriority) Len() int      { return len(r) }
+func (r recordingsByPriority) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r recordingsByPriority) Less(i, j int) bool {
+	if r[i].Priority != r[j].Priority {
+		return r[i].Priority > r[j].Priority
+	}
+	return recordingsByStart(r).Less(i, j)
+}
+
+type airingsByPreference []*Airing
+
+func (a airingsByPreference) Len() int           { return len(a) }
+func (a airingsByPreference) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a airingsByPreference) Less(i, j int) bool { return betterAiring(a[i], a[j]) }
+
+type tunerRecordingsByStart []TunerRecording
+
+func (r tunerRecordingsByStart) Len() int      { return len(r) }
+func (r tunerRecordingsByStart) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r tunerRecordingsByStart) Less(i, j int) bool {
+	if !r[i].Start.Equal(r[j].Start) {
+		return r[i].Start.Before(r[j].Start)
+	}
+	return r[i].StationID < r[j].StationID
+}
@@ -0,0 +1,178 @@
+package schedulesdirect
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveConflicts(t *testing.T) {
+	s1, err := JsonToSchedules([]byte(`{"stationID":"10001","programs":[{"airDateTime":"2014-07-30T01:00:00Z","duration":3600,"programID":"EP000000010001"},{"airDateTime":"2014-07-30T05:00:00Z","duration":3600,"programID":"EP000000010001"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2, err := JsonToSchedules([]byte(`{"stationID":"10002","programs":[{"airDateTime":"2014-07-30T01:00:00Z","duration":3600,"programID":"EP000000020001"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s3, err := JsonToSchedules([]byte(`{"stationID":"10003","programs":[{"airDateTime":"2014-07-30T01:30:00Z","duration":3600,"programID":"EP000000030001"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s4, err := JsonToSchedules([]byte(`{"stationID":"10004","programs":[{"airDateTime":"2014-07-30T01:00:00Z","duration":3600,"programID":"EP000000040001"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewGuide([]schedule{s1, s2, s3, s4}, []program{})
+
+	var cable, antenna channelMapping
+	cable.Metadata.Transport = "Cable"
+	cable.Map = []channelMapEntry{{"1", "10001"}, {"2", "10002"}, {"3", "10003"}}
+	antenna.Metadata.Transport = "Antenna"
+	antenna.Map = []channelMapEntry{{"4.1", "10004"}}
+
+	si := MergeChannelMappings(cable, antenna)
+
+	plan, err := PlanRecordings(g, []Rule{
+		{Name: "one", SeriesID: "SH000000010000"},
+		{Name: "two", SeriesID: "SH000000020000", Priority: 1},
+		{Name: "three", SeriesID: "SH000000030000", Priority: 1},
+		{Name: "four", SeriesID: "SH000000040000"},
+	}, testTime("2014-07-30T00:00:00Z"), testTime("2014-07-30T06:00:00Z"), []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan) != 4 {
+		t.Fatalf("len(plan) != 4: %v", plan)
+	}
+
+	tp := ResolveConflicts(g, si, plan, map[string]int{"Cable": 2})
+
+	if len(tp.Recordings) != 3 {
+		t.Fatalf("len(tp.Recordings) != 3: %v", tp.Recordings)
+	}
+
+	// EP000000010001 moved to its other airing
+	last := tp.Recordings[2]
+	if last.ProgramID != "EP000000010001" || !last.Alternate || !last.Start.Equal(testTime("2014-07-30T05:00:00Z")) {
+		t.Fatalf("last doesn't match: %v", last)
+	}
+
+	if len(tp.Conflicts) != 1 {
+		t.Fatalf("len(tp.Conflicts) != 1: %v", tp.Conflicts)
+	}
+	if tp.Conflicts[0].Recording.ProgramID != "EP000000040001" || !strings.Contains(tp.Conflicts[0].Reason, "no tuner") {
+		t.Fatalf("tp.Conflicts[0] doesn't match: %v", tp.Conflicts[0])
+	}
+
+	tp = ResolveConflicts(g, si, plan, map[string]int{"Cable": 1, "Antenna": 1})
+	if len(tp.Conflicts) != 1 || tp.Conflicts[0].Recording.ProgramID != "EP000000030001" {
+		t.Fatalf("tp.Conflicts doesn't match: %v", tp.Conflicts)
+	}
+	if len(tp.Conflicts[0].With) != 1 || !strings.Contains(tp.Conflicts[0].Reason, "busy with EP000000020001") {
+		t.Fatalf("tp.Conflicts[0] doesn't match: %v", tp.Conflicts[0])
+	}
+}
+
+func TestResolveConflictsAlternates(t *testing.T) {
+	s1, err := JsonToSchedules([]byte(`{"stationID":"10001","programs":[
+{"airDateTime":"2014-07-29T01:00:00Z","duration":3600,"programID":"EP000000050001","new":true},
+{"airDateTime":"2014-07-30T01:00:00Z","duration":3600,"programID":"EP000000050001","new":true},
+{"airDateTime":"2014-07-30T03:00:00Z","duration":3600,"programID":"EP000000050001"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2, err := JsonToSchedules([]byte(`{"stationID":"10002","programs":[{"airDateTime":"2014-07-30T01:00:00Z","duration":3600,"programID":"EP000000060001"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s3, err := JsonToSchedules([]byte(`{"stationID":"10003","programs":[{"airDateTime":"2014-07-30T04:00:00Z","duration":3600,"programID":"EP000000050001","new":true}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewGuide([]schedule{s1, s2, s3}, []program{})
+
+	var cable channelMapping
+	cable.Metadata.Transport = "Cable"
+	cable.Map = []channelMapEntry{{"1", "10001"}, {"2", "10002"}, {"3", "10003"}}
+
+	si := MergeChannelMappings(cable)
+
+	rules := []Rule{
+		{Name: "five", SeriesID: "SH000000050000", NewOnly: true, StationIDs: []string{"10001"}},
+		{Name: "six", SeriesID: "SH000000060000", Priority: 1},
+	}
+
+	plan, err := PlanRecordings(g, rules, testTime("2014-07-30T00:00:00Z"), testTime("2014-07-30T06:00:00Z"), []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan) != 2 {
+		t.Fatalf("len(plan) != 2: %v", plan)
+	}
+
+	// the airing of the day before is past, the one of 03:00 isn't new and
+	// 10003 isn't one of the rule's stations
+	tp := ResolveConflicts(g, si, plan, map[string]int{"Cable": 1})
+	if len(tp.Recordings) != 1 || tp.Recordings[0].ProgramID != "EP000000060001" {
+		t.Fatalf("tp.Recordings doesn't match: %v", tp.Recordings)
+	}
+	if len(tp.Conflicts) != 1 || tp.Conflicts[0].Recording.ProgramID != "EP000000050001" {
+		t.Fatalf("tp.Conflicts doesn't match: %v", tp.Conflicts)
+	}
+
+	// without the station restriction the airing of 10003 can be used
+	rules[0].StationIDs = nil
+
+	plan, err = PlanRecordings(g, rules, testTime("2014-07-30T00:00:00Z"), testTime("2014-07-30T06:00:00Z"), []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tp = ResolveConflicts(g, si, plan, map[string]int{"Cable": 1})
+	if len(tp.Conflicts) != 0 || len(tp.Recordings) != 2 {
+		t.Fatalf("tp doesn't match: %v", tp)
+	}
+	if alt := tp.Recordings[1]; !alt.Alternate || alt.StationID != "10003" {
+		t.Fatalf("alt doesn't match: %v", alt)
+	}
+}
+
+func TestResolveConflictsEarlierAlternate(t *testing.T) {
+	s1, err := JsonToSchedules([]byte(`{"stationID":"10001","programs":[
+{"airDateTime":"2014-07-30T01:00:00Z","duration":3600,"programID":"EP000000050001"},
+{"airDateTime":"2014-07-30T03:00:00Z","duration":3600,"programID":"EP000000050001","videoProperties":["hdtv"]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2, err := JsonToSchedules([]byte(`{"stationID":"10002","programs":[{"airDateTime":"2014-07-30T03:00:00Z","duration":3600,"programID":"EP000000060001"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewGuide([]schedule{s1, s2}, []program{})
+
+	var cable channelMapping
+	cable.Metadata.Transport = "Cable"
+	cable.Map = []channelMapEntry{{"1", "10001"}, {"2", "10002"}}
+
+	si := MergeChannelMappings(cable)
+
+	plan, err := PlanRecordings(g, []Rule{
+		{Name: "five", SeriesID: "SH000000050000"},
+		{Name: "six", SeriesID: "SH000000060000", Priority: 1},
+	}, testTime("2014-07-30T00:00:00Z"), testTime("2014-07-30T06:00:00Z"), []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the HD airing of 03:00 is planned, the one of 01:00 is free
+	tp := ResolveConflicts(g, si, plan, map[string]int{"Cable": 1})
+	if len(tp.Conflicts) != 0 || len(tp.Recordings) != 2 {
+		t.Fatalf("tp doesn't match: %v", tp)
+	}
+	if alt := tp.Recordings[0]; !alt.Alternate || alt.ProgramID != "EP000000050001" || !alt.Start.Equal(testTime("2014-07-30T01:00:00Z")) {
+		t.Fatalf("alt doesn't match: %v", alt)
+	}
+}
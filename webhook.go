@@ -0,0 +1,374 @@
+package schedulesdirect
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	EventLineupChanged    = "lineup.changed"
+	EventSchedulesUpdated = "schedules.updated"
+	EventProgramsChanged  = "programs.changed"
+	EventAccountExpiring  = "account.expiring"
+)
+
+const (
+	defaultWebhookAttempts   = 3
+	defaultWebhookBackoff    = time.Second
+	defaultWebhookTimeout    = 10 * time.Second
+	defaultWebhookDeliveries = 1000
+)
+
+// WebhookEvent is the JSON body posted to the webhooks.
+type WebhookEvent struct {
+	ID   string      `json:"id"`
+	Type string      `json:"type"`
+	Time time.Time   `json:"time"`
+	Data interface{} `json:"data"`
+}
+
+type LineupChanges struct {
+	Added    []string `json:"added"`
+	Removed  []string `json:"removed"`
+	Modified []string `json:"modified"`
+}
+
+// StationUpdate counts the changes of a station's airings.
+type StationUpdate struct {
+	StationID string `json:"stationID"`
+	Added     int    `json:"added"`
+	Removed   int    `json:"removed"`
+	Moved     int    `json:"moved"`
+	Changed   int    `json:"changed"`
+}
+
+type ProgramChanges struct {
+	ProgramIDs []string `json:"programIDs"`
+}
+
+type AccountExpiring struct {
+	Expires time.Time `json:"expires"`
+}
+
+func newEventID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+func newWebhookEvent(eventType string, t time.Time, data interface{}) WebhookEvent {
+	return WebhookEvent{newEventID(), eventType, t, data}
+}
+
+// Refresh holds the data before and after a refresh. The old values are
+// empty on the first refresh.
+type Refresh struct {
+	Time time.Time
+
+	OldStatus status
+	NewStatus status
+
+	OldSchedules []schedule
+	NewSchedules []schedule
+	OldPrograms  []program
+	NewPrograms  []program
+
+	// an account.expiring event is sent when the account expires within it
+	ExpiringWithin time.Duration
+}
+
+// Events returns the events of a refresh: the lineups of the status added,
+// removed or modified, the stations with airing changes (see DiffSchedules),
+// the programs with a different md5 and the account expiring.
+func (r Refresh) Events() []WebhookEvent {
+	events := []WebhookEvent{}
+
+	if lc, ok := diffLineups(r.OldStatus, r.NewStatus); ok {
+		events = append(events, newWebhookEvent(EventLineupChanged, r.Time, lc))
+	}
+
+	if updates := stationUpdates(DiffSchedules(r.OldSchedules, r.OldPrograms, r.NewSchedules, r.NewPrograms)); len(updates) > 0 {
+		events = append(events, newWebhookEvent(EventSchedulesUpdated, r.Time, updates))
+	}
+
+	if ids := changedPrograms(r.OldPrograms, r.NewPrograms); len(ids) > 0 {
+		events = append(events, newWebhookEvent(EventProgramsChanged, r.Time, ProgramChanges{ids}))
+	}
+
+	if expires := r.NewStatus.Account.Expires; !expires.IsZero() && r.ExpiringWithin > 0 && expires.Sub(r.Time) < r.ExpiringWithin {
+		events = append(events, newWebhookEvent(EventAccountExpiring, r.Time, AccountExpiring{expires}))
+	}
+
+	return events
+}
+
+func diffLineups(o, n status) (LineupChanges, bool) {
+	lc := LineupChanges{Added: []string{}, Removed: []string{}, Modified: []string{}}
+
+	modified := make(map[string]time.Time, len(o.Lineups))
+	for _, l := range o.Lineups {
+		modified[l.ID] = l.Modified
+	}
+
+	seen := make(map[string]bool, len(n.Lineups))
+	for _, l := range n.Lineups {
+		seen[l.ID] = true
+		if m, ok := modified[l.ID]; !ok {
+			lc.Added = append(lc.Added, l.ID)
+		} else if !m.Equal(l.Modified) {
+			lc.Modified = append(lc.Modified, l.ID)
+		}
+	}
+
+	for _, l := range o.Lineups {
+		if !seen[l.ID] {
+			lc.Removed = append(lc.Removed, l.ID)
+		}
+	}
+
+	sort.Strings(lc.Added)
+	sort.Strings(lc.Removed)
+	sort.Strings(lc.Modified)
+
+	return lc, len(lc.Added)+len(lc.Removed)+len(lc.Modified) > 0
+}
+
+func stationUpdates(changes []Change) []StationUpdate {
+	updates := []StationUpdate{}
+
+	// changes are sorted by station
+	for _, c := range changes {
+		if len(updates) == 0 || updates[len(updates)-1].StationID != c.StationID {
+			updates = append(updates, StationUpdate{StationID: c.StationID})
+		}
+
+		u := &updates[len(updates)-1]
+		switch c.Kind {
+		case AiringAdded:
+			u.Added++
+		case AiringRemoved:
+			u.Removed++
+		case AiringMoved:
+			u.Moved++
+		case AiringChanged:
+			u.Changed++
+		}
+	}
+
+	return updates
+}
+
+func changedPrograms(o, n []program) []string {
+	md5s := make(map[string]string, len(o))
+	for _, p := range o {
+		md5s[p.ProgramID] = p.Md5
+	}
+
+	ids := []string{}
+	for _, p := range n {
+		if m, ok := md5s[p.ProgramID]; ok && m != p.Md5 {
+			ids = append(ids, p.ProgramID)
+		}
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
+
+// WebhookEndpoint receives the events of Events, all of them when empty.
+type WebhookEndpoint struct {
+	URL    string
+	Secret string
+	Events []string
+}
+
+// WebhookDelivery is an attempt to post an event to an endpoint.
+type WebhookDelivery struct {
+	EventID    string
+	EventType  string
+	URL        string
+	Attempt    int
+	Time       time.Time
+	StatusCode int
+	Error      string
+}
+
+// WebhookPublisher posts events to endpoints. The body is signed with the
+// secret of the endpoint, see WebhookSignature. A delivery is retried up to
+// MaxAttempts times on network errors, 429 and 5xx responses, waiting Backoff
+// and then twice as long each time. Each attempt is given Timeout (10 seconds
+// when <= 0) and the log keeps the last MaxDeliveries deliveries (1000 when
+// <= 0).
+type WebhookPublisher struct {
+	Endpoints     []WebhookEndpoint
+	MaxAttempts   int
+	Backoff       time.Duration
+	Timeout       time.Duration
+	MaxDeliveries int
+
+	mu  sync.Mutex
+	log []WebhookDelivery
+}
+
+func NewWebhookPublisher(endpoints ...WebhookEndpoint) *WebhookPublisher {
+	return &WebhookPublisher{
+		Endpoints:     endpoints,
+		MaxAttempts:   defaultWebhookAttempts,
+		Backoff:       defaultWebhookBackoff,
+		Timeout:       defaultWebhookTimeout,
+		MaxDeliveries: defaultWebhookDeliveries,
+	}
+}
+
+// WebhookSignature returns the value of the X-Schedulesdirect-Signature header:
+// "sha256=" and the hex HMAC-SHA256 of the body.
+func WebhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature checks the signature of a received body.
+func VerifyWebhookSignature(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(WebhookSignature(secret, body)), []byte(signature))
+}
+
+// Publish posts the events to the endpoints subscribed to them. The error is
+// non-nil when a delivery failed after all its attempts, see Deliveries.
+func (wp *WebhookPublisher) Publish(events []WebhookEvent) error {
+	var failed []string
+
+	for _, e := range events {
+		body, errEncode := json.Marshal(e)
+		if errEncode != nil {
+			return errEncode
+		}
+
+		for _, endpoint := range wp.Endpoints {
+			if len(endpoint.Events) > 0 && !containsString(endpoint.Events, e.Type) {
+				continue
+			}
+
+			if !wp.deliver(endpoint, e, body) {
+				failed = append(failed, fmt.Sprintf("%s to %s", e.ID, endpoint.URL))
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("webhook delivery failed: %s", strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+func (wp *WebhookPublisher) deliver(endpoint WebhookEndpoint, e WebhookEvent, body []byte) bool {
+	attempts := wp.MaxAttempts
+	if attempts <= 0 {
+		attempts = 1
+	}
+
+	backoff := wp.Backoff
+
+	timeout := wp.Timeout
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		statusCode, err := postWebhook(endpoint, e, body, timeout)
+
+		d := WebhookDelivery{
+			EventID:    e.ID,
+			EventType:  e.Type,
+			URL:        endpoint.URL,
+			Attempt:    attempt,
+			Time:       time.Now(),
+			StatusCode: statusCode,
+		}
+		if err != nil {
+			d.Error = err.Error()
+		}
+
+		wp.logDelivery(d)
+
+		if err == nil {
+			return true
+		}
+
+		// the other client errors won't be fixed by retrying
+		if statusCode >= 400 && statusCode < 500 && statusCode != 429 {
+			return false
+		}
+	}
+
+	return false
+}
+
+func (wp *WebhookPublisher) logDelivery(d WebhookDelivery) {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	max := wp.MaxDeliveries
+	if max <= 0 {
+		max = defaultWebhookDeliveries
+	}
+
+	wp.log = append(wp.log, d)
+	if len(wp.log) > max {
+		wp.log = append([]WebhookDelivery{}, wp.log[len(wp.log)-max:]...)
+	}
+}
+
+func postWebhook(endpoint WebhookEndpoint, e WebhookEvent, body []byte, timeout time.Duration) (int, error) {
+	clientHttp := http.Client{Timeout: timeout}
+
+	req, errNewRequest := http.NewRequest("POST", endpoint.URL, bytes.NewReader(body))
+	if errNewRequest != nil {
+		return 0, errNewRequest
+	}
+
+	req.Header.Add("User-Agent", "go-schedulesdirect")
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("X-Schedulesdirect-Event", e.Type)
+	req.Header.Add("X-Schedulesdirect-Delivery", e.ID)
+	req.Header.Add("X-Schedulesdirect-Signature", WebhookSignature(endpoint.Secret, body))
+
+	resp, errDo := clientHttp.Do(req)
+	if errDo != nil {
+		return 0, errDo
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("resp.StatusCode != 2xx: %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Deliveries returns the delivery log, the oldest first.
+func (wp *WebhookPublisher) Deliveries() []WebhookDelivery {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	return append([]WebhookDelivery{}, wp.log...)
+}
@@ -0,0 +1,159 @@
+package schedulesdirect
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRefreshEvents(t *testing.T) {
+	var o, n status
+	if err := json.Unmarshal([]byte(`{"lineups":[{"ID":"USA-NY67791-X","modified":"2014-07-29T13:00:00Z"},{"ID":"USA-OTA-10001","modified":"2014-07-29T13:00:00Z"}]}`), &o); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(`{"account":{"expires":"2014-08-05T00:00:00Z"},"lineups":[{"ID":"USA-NY67791-X","modified":"2014-07-30T13:00:00Z"},{"ID":"USA-PA-12345-X","modified":"2014-07-30T13:00:00Z"}]}`), &n); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := JsonToSchedules([]byte(`{"stationID":"10001","programs":[{"airDateTime":"2014-07-30T01:00:00Z","duration":1800,"programID":"EP000000010001"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := Refresh{
+		Time:           testTime("2014-07-30T00:00:00Z"),
+		OldStatus:      o,
+		NewStatus:      n,
+		NewSchedules:   []schedule{s},
+		OldPrograms:    []program{{ProgramID: "EP000000010001", Md5: "a"}},
+		NewPrograms:    []program{{ProgramID: "EP000000010001", Md5: "b"}},
+		ExpiringWithin: 7 * 24 * time.Hour,
+	}
+
+	events := r.Events()
+	if len(events) != 4 {
+		t.Fatalf("len(events) != 4: %v", events)
+	}
+
+	for i, eventType := range []string{EventLineupChanged, EventSchedulesUpdated, EventProgramsChanged, EventAccountExpiring} {
+		if events[i].Type != eventType || events[i].ID == "" {
+			t.Fatalf("events[%d] doesn't match: %v", i, events[i])
+		}
+	}
+
+	lc := events[0].Data.(LineupChanges)
+	if len(lc.Added) != 1 || lc.Added[0] != "USA-PA-12345-X" || len(lc.Removed) != 1 || lc.Removed[0] != "USA-OTA-10001" || len(lc.Modified) != 1 || lc.Modified[0] != "USA-NY67791-X" {
+		t.Fatalf("lc doesn't match: %v", lc)
+	}
+
+	updates := events[1].Data.([]StationUpdate)
+	if len(updates) != 1 || updates[0].StationID != "10001" || updates[0].Added != 1 {
+		t.Fatalf("updates doesn't match: %v", updates)
+	}
+
+	r.ExpiringWithin = 24 * time.Hour
+	if events := r.Events(); len(events) != 3 {
+		t.Fatalf("len(events) != 3: %v", events)
+	}
+}
+
+func TestWebhookPublisher(t *testing.T) {
+	setup()
+	defer server.Close()
+
+	attempts := 0
+	mux.HandleFunc("/hook", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testHeader(t, r, "X-Schedulesdirect-Event", EventProgramsChanged)
+
+		body, errRead := ioutil.ReadAll(r.Body)
+		if errRead != nil {
+			t.Fatal(errRead)
+		}
+		if !VerifyWebhookSignature("secret", body, r.Header.Get("X-Schedulesdirect-Signature")) {
+			t.Fatal("signature doesn't match")
+		}
+
+		var e WebhookEvent
+		if err := json.Unmarshal(body, &e); err != nil {
+			t.Fatal(err)
+		}
+		testHeader(t, r, "X-Schedulesdirect-Delivery", e.ID)
+
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("/bad", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	wp := NewWebhookPublisher(
+		WebhookEndpoint{URL: server.URL + "/hook", Secret: "secret", Events: []string{EventProgramsChanged}},
+		WebhookEndpoint{URL: server.URL + "/bad", Secret: "secret", Events: []string{EventAccountExpiring}},
+	)
+	wp.Backoff = 0
+
+	events := []WebhookEvent{newWebhookEvent(EventProgramsChanged, testTime("2014-07-30T00:00:00Z"), ProgramChanges{[]string{"EP000000010001"}})}
+
+	if err := wp.Publish(events); err != nil {
+		t.Fatal(err)
+	}
+
+	deliveries := wp.Deliveries()
+	if len(deliveries) != 2 {
+		t.Fatalf("len(deliveries) != 2: %v", deliveries)
+	}
+	if deliveries[0].StatusCode != 503 || deliveries[0].Error == "" || deliveries[1].StatusCode != 204 || deliveries[1].Attempt != 2 {
+		t.Fatalf("deliveries doesn't match: %v", deliveries)
+	}
+
+	// a 400 isn't retried
+	events = []WebhookEvent{newWebhookEvent(EventAccountExpiring, testTime("2014-07-30T00:00:00Z"), AccountExpiring{testTime("2014-08-05T00:00:00Z")})}
+	if err := wp.Publish(events); err == nil {
+		t.Fatal("err == nil")
+	}
+
+	deliveries = wp.Deliveries()
+	if len(deliveries) != 3 || deliveries[2].StatusCode != 400 {
+		t.Fatalf("deliveries doesn't match: %v", deliveries)
+	}
+}
+
+func TestWebhookPublisherTimeout(t *testing.T) {
+	setup()
+	defer server.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+
+	mux.HandleFunc("/hang", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+		}
+	})
+
+	wp := NewWebhookPublisher(WebhookEndpoint{URL: server.URL + "/hang", Secret: "secret"})
+	wp.Backoff = 0
+	wp.Timeout = 50 * time.Millisecond
+	wp.MaxDeliveries = 2
+
+	events := []WebhookEvent{newWebhookEvent(EventProgramsChanged, testTime("2014-07-30T00:00:00Z"), ProgramChanges{[]string{"EP000000010001"}})}
+
+	if err := wp.Publish(events); err == nil {
+		t.Fatal("err == nil")
+	}
+
+	// 3 attempts, the log keeps the last 2
+	deliveries := wp.Deliveries()
+	if len(deliveries) != 2 || deliveries[0].Attempt != 2 || deliveries[1].Attempt != 3 || deliveries[1].Error == "" {
+		t.Fatalf("deliveries doesn't match: %v", deliveries)
+	}
+}